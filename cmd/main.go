@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -13,6 +15,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/api/route"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 )
 
 func main() {
@@ -26,6 +29,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 🔹 `reconcile` applies the quota profiles to every onboarded namespace, then exits
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		os.Exit(runReconcile(app, os.Args[2:]))
+	}
+
 	env := app.Env
 
 	r := chi.NewRouter()
@@ -56,12 +64,23 @@ func main() {
 		MaxAge:           300,
 	}))
 
+	r.Get("/readyz", route.Readiness(app))
+
+	if app.MetricsHandler != nil {
+		r.Handle(env.Metrics.Path, app.MetricsHandler)
+	}
+
 	apiHandler, err := route.Setup(app)
 	if err != nil {
 		slog.Error("failed to set up routes", slog.Any("error", err))
 		os.Exit(1)
 	}
 
+	if err := route.StartReaper(context.Background(), app); err != nil {
+		slog.Error("failed to start idle namespace reaper", slog.Any("error", err))
+		os.Exit(1)
+	}
+
 	r.Mount(
 		env.Server.ContextPath,
 		http.StripPrefix(env.Server.ContextPath, apiHandler),
@@ -80,3 +99,27 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runReconcile reconciles the quotas of every onboarded namespace once and prints a summary.
+// It returns the exit code of the command.
+func runReconcile(app *bootstrap.Application, args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "only report what would change")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx := context.Background()
+	if *dryRun {
+		ctx = domain.WithDryRun(ctx)
+	}
+
+	summary, err := route.ReconcileQuotas(ctx, app)
+	fmt.Println(summary)
+	if err != nil {
+		slog.Error("failed to reconcile quotas", slog.Any("error", err))
+		return 1
+	}
+
+	return 0
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -58,11 +59,31 @@ func (c *OnboardingController) Onboard(
 		}
 	}
 
-	err := c.OnboardingUsecase.Onboard(ctx, domain.OnboardingRequest{
-		Group:     groupPtr,
-		UserName:  user.Username,
-		UserRoles: user.Roles,
+	result, err := c.OnboardingUsecase.Onboard(ctx, domain.OnboardingRequest{
+		Group:      groupPtr,
+		UserName:   user.Username,
+		UserRoles:  user.Roles,
+		UserGroups: user.Groups,
+		DryRun:     req.DryRun.Or(false),
 	})
+	if errors.Is(err, domain.ErrNamespaceCollision) {
+		slog.ErrorContext(ctx, "❌ Namespace belongs to another user or group",
+			slog.Any("error", err),
+		)
+		return &api.OnboardConflict{}, nil
+	}
+	if errors.Is(err, domain.ErrNamespaceNotOwned) {
+		slog.ErrorContext(ctx, "❌ Namespace is reserved or was not created by onboarding",
+			slog.Any("error", err),
+		)
+		return &api.OnboardConflict{}, nil
+	}
+	if errors.Is(err, domain.ErrFieldManagerConflict) {
+		slog.ErrorContext(ctx, "❌ Namespace or quota fields are managed by someone else",
+			slog.Any("error", err),
+		)
+		return &api.OnboardConflict{}, nil
+	}
 	if err != nil {
 		slog.ErrorContext(ctx, "❌ Onboarding failed",
 			slog.Any("error", err),
@@ -70,6 +91,158 @@ func (c *OnboardingController) Onboard(
 		return &api.OnboardForbidden{}, err
 	}
 
-	slog.InfoContext(ctx, "✅ Onboarding successful")
-	return &api.OnboardOK{}, nil
+	slog.InfoContext(ctx, "✅ Onboarding successful",
+		slog.String("namespace", result.Namespace),
+		slog.Bool("dryRun", result.DryRun),
+		slog.Bool("skipped", result.Skipped),
+	)
+	return convertOnboardingResult(result), nil
+}
+
+func convertOnboardingResult(result domain.OnboardingResult) *api.OnboardingResult {
+	res := &api.OnboardingResult{
+		Namespace: result.Namespace,
+		DryRun:    result.DryRun,
+		Skipped:   result.Skipped,
+	}
+
+	if result.Annotations != nil {
+		res.Annotations = api.NewOptOnboardingResultAnnotations(result.Annotations)
+	}
+	if result.Labels != nil {
+		res.Labels = api.NewOptOnboardingResultLabels(result.Labels)
+	}
+	if result.QuotaProfile != "" {
+		res.QuotaProfile = api.NewOptString(result.QuotaProfile)
+	}
+
+	return res
+}
+
+func (c *OnboardingController) Offboard(
+	ctx context.Context,
+	req *api.OffboardingRequest,
+) (api.OffboardRes, error) {
+	slog.Info("🟢 Received Offboarding Request")
+
+	user, ok := c.UserContextReader.GetUser(ctx)
+	if !ok || user == nil {
+		err := fmt.Errorf("user not found in context")
+		slog.Error("❌ Failed to retrieve user from context", slog.Any("error", err))
+		return &api.OffboardForbidden{}, err
+	}
+
+	slog.InfoContext(ctx, "🔵 User identified")
+
+	var groupPtr *string
+	if req.Group.Set {
+		groupPtr = &req.Group.Value
+
+		// ✅ Check if the requested group is in user's groups
+		if !slices.Contains(user.Groups, *groupPtr) {
+			err := fmt.Errorf("user does not have access to group: %s", *groupPtr)
+			slog.ErrorContext(ctx, "❌ Unauthorized group access",
+				slog.String("group", *groupPtr),
+				slog.Any("userGroups", user.Groups),
+				slog.Any("error", err),
+			)
+			return &api.OffboardUnauthorized{}, err
+		}
+	}
+
+	err := c.OnboardingUsecase.Offboard(ctx, domain.OffboardingRequest{
+		Group:    groupPtr,
+		UserName: user.Username,
+	})
+	if errors.Is(err, domain.ErrNamespaceCollision) ||
+		errors.Is(err, domain.ErrNamespaceNotOwned) {
+		slog.ErrorContext(ctx, "❌ Namespace belongs to another user or group or was not onboarded",
+			slog.Any("error", err),
+		)
+		return &api.OffboardConflict{}, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Offboarding failed",
+			slog.Any("error", err),
+		)
+		return &api.OffboardForbidden{}, err
+	}
+
+	slog.InfoContext(ctx, "✅ Offboarding successful")
+	return &api.OffboardOK{}, nil
+}
+
+func (c *OnboardingController) GetOnboardingStatus(
+	ctx context.Context,
+	params api.GetOnboardingStatusParams,
+) (api.GetOnboardingStatusRes, error) {
+	slog.Info("🟢 Received Onboarding Status Request")
+
+	user, ok := c.UserContextReader.GetUser(ctx)
+	if !ok || user == nil {
+		err := fmt.Errorf("user not found in context")
+		slog.Error("❌ Failed to retrieve user from context", slog.Any("error", err))
+		return &api.GetOnboardingStatusForbidden{}, err
+	}
+
+	var groupPtr *string
+	if params.Group.Set {
+		groupPtr = &params.Group.Value
+
+		// ✅ Check if the requested group is in user's groups
+		if !slices.Contains(user.Groups, *groupPtr) {
+			err := fmt.Errorf("user does not have access to group: %s", *groupPtr)
+			slog.ErrorContext(ctx, "❌ Unauthorized group access",
+				slog.String("group", *groupPtr),
+				slog.Any("userGroups", user.Groups),
+				slog.Any("error", err),
+			)
+			return &api.GetOnboardingStatusUnauthorized{}, err
+		}
+	}
+
+	status, err := c.OnboardingUsecase.Status(ctx, domain.OnboardingRequest{
+		Group:      groupPtr,
+		UserName:   user.Username,
+		UserRoles:  user.Roles,
+		UserGroups: user.Groups,
+	})
+	if errors.Is(err, domain.ErrNamespaceCollision) ||
+		errors.Is(err, domain.ErrNamespaceNotOwned) {
+		slog.ErrorContext(ctx, "❌ Namespace belongs to another user or group or was not onboarded",
+			slog.Any("error", err),
+		)
+		return &api.GetOnboardingStatusConflict{}, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to get onboarding status",
+			slog.Any("error", err),
+		)
+		return &api.GetOnboardingStatusForbidden{}, err
+	}
+
+	return convertNamespaceStatus(status), nil
+}
+
+func convertNamespaceStatus(status domain.NamespaceStatus) *api.OnboardingStatus {
+	res := &api.OnboardingStatus{
+		Namespace: status.Name,
+		Exists:    status.Exists,
+	}
+
+	if status.Annotations != nil {
+		res.Annotations = api.NewOptOnboardingStatusAnnotations(status.Annotations)
+	}
+	if status.Labels != nil {
+		res.Labels = api.NewOptOnboardingStatusLabels(status.Labels)
+	}
+	if status.Quota != nil {
+		res.Quota = api.NewOptQuotaStatus(api.QuotaStatus{
+			Hard:    status.Quota.Hard,
+			Used:    status.Quota.Used,
+			Ignored: status.Quota.Ignored,
+		})
+	}
+
+	return res
 }
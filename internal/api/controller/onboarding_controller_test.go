@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	api "github.com/onyxia-datalab/onyxia-onboarding/internal/api/oas"
@@ -20,11 +21,34 @@ type MockOnboardingUsecase struct {
 
 var _ domain.OnboardingUsecase = (*MockOnboardingUsecase)(nil)
 
-func (m *MockOnboardingUsecase) Onboard(ctx context.Context, req domain.OnboardingRequest) error {
+func (m *MockOnboardingUsecase) Onboard(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (domain.OnboardingResult, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(domain.OnboardingResult), args.Error(1)
+}
+
+func (m *MockOnboardingUsecase) Offboard(ctx context.Context, req domain.OffboardingRequest) error {
 	args := m.Called(ctx, req)
 	return args.Error(0)
 }
 
+func (m *MockOnboardingUsecase) Status(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (domain.NamespaceStatus, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(domain.NamespaceStatus), args.Error(1)
+}
+
+func (m *MockOnboardingUsecase) ReconcileQuotas(
+	ctx context.Context,
+) (domain.QuotaReconcileSummary, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(domain.QuotaReconcileSummary), args.Error(1)
+}
+
 // ✅ Test Setup Function
 func setupController(
 	mockUsecase *MockOnboardingUsecase,
@@ -46,7 +70,8 @@ func TestOnboardingController_Onboard_Success_NoGroup(t *testing.T) {
 		Roles:    []string{"role1"},
 	})
 
-	mockUsecase.On("Onboard", mock.Anything, mock.Anything).Return(nil)
+	mockUsecase.On("Onboard", mock.Anything, mock.Anything).
+		Return(domain.OnboardingResult{Namespace: "user-test-user"}, nil)
 
 	controller := setupController(mockUsecase, mockUserCtx)
 	req := api.OnboardingRequest{Group: api.OptString{Set: false}}
@@ -54,10 +79,55 @@ func TestOnboardingController_Onboard_Success_NoGroup(t *testing.T) {
 	res, err := controller.Onboard(context.Background(), &req)
 
 	assert.NoError(t, err)
-	assert.IsType(t, &api.OnboardOK{}, res)
+	assert.Equal(t, &api.OnboardingResult{Namespace: "user-test-user"}, res)
 	mockUsecase.AssertCalled(t, "Onboard", mock.Anything, mock.Anything)
 }
 
+func TestOnboardingController_Onboard_DryRun(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{Username: "test-user"})
+
+	mockUsecase.On("Onboard", mock.Anything, domain.OnboardingRequest{
+		UserName: "test-user",
+		DryRun:   true,
+	}).Return(domain.OnboardingResult{
+		Namespace:    "user-test-user",
+		Annotations:  map[string]string{"onyxia.sh/identity": "user:test-user"},
+		QuotaProfile: "default",
+		DryRun:       true,
+	}, nil)
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OnboardingRequest{DryRun: api.NewOptBool(true)}
+
+	res, err := controller.Onboard(context.Background(), &req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &api.OnboardingResult{
+		Namespace: "user-test-user",
+		Annotations: api.NewOptOnboardingResultAnnotations(
+			api.OnboardingResultAnnotations{"onyxia.sh/identity": "user:test-user"},
+		),
+		QuotaProfile: api.NewOptString("default"),
+		DryRun:       true,
+	}, res)
+}
+
+func TestOnboardingController_Onboard_Skipped(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{Username: "test-user"})
+
+	mockUsecase.On("Onboard", mock.Anything, domain.OnboardingRequest{UserName: "test-user"}).
+		Return(domain.OnboardingResult{Namespace: "user-test-user", Skipped: true}, nil)
+
+	controller := setupController(mockUsecase, mockUserCtx)
+
+	res, err := controller.Onboard(context.Background(), &api.OnboardingRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &api.OnboardingResult{Namespace: "user-test-user", Skipped: true}, res)
+}
+
 func TestOnboardingController_Onboard_GetUserFails(t *testing.T) {
 	mockUsecase := new(MockOnboardingUsecase)
 	mockUserCtx, _ := usercontext.NewFakeUserContext(nil) // ❌ GetUser fails
@@ -99,7 +169,7 @@ func TestOnboardingController_Onboard_OnboardingFails(t *testing.T) {
 	})
 
 	mockUsecase.On("Onboard", mock.Anything, mock.Anything).
-		Return(errors.New("onboarding service error"))
+		Return(domain.OnboardingResult{}, errors.New("onboarding service error"))
 
 	controller := setupController(mockUsecase, mockUserCtx)
 	req := api.OnboardingRequest{Group: api.OptString{Value: "test-group", Set: true}}
@@ -111,3 +181,233 @@ func TestOnboardingController_Onboard_OnboardingFails(t *testing.T) {
 
 	mockUsecase.AssertCalled(t, "Onboard", mock.Anything, mock.Anything)
 }
+
+func TestOnboardingController_Onboard_NamespaceCollision(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Username: "Test.User",
+		Groups:   []string{"test-group"},
+	})
+
+	mockUsecase.On("Onboard", mock.Anything, mock.Anything).
+		Return(domain.OnboardingResult{}, fmt.Errorf(
+			"%w: namespace user-test-user belongs to user:test-user",
+			domain.ErrNamespaceCollision,
+		))
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OnboardingRequest{Group: api.OptString{Set: false}}
+
+	res, err := controller.Onboard(context.Background(), &req)
+
+	assert.NoError(t, err)
+	assert.IsType(t, &api.OnboardConflict{}, res)
+}
+
+func TestOnboardingController_Onboard_NamespaceNotOwned(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{Username: "kube"})
+
+	mockUsecase.On("Onboard", mock.Anything, mock.Anything).
+		Return(domain.OnboardingResult{}, fmt.Errorf(
+			"%w: namespace kube is reserved",
+			domain.ErrNamespaceNotOwned,
+		))
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OnboardingRequest{Group: api.OptString{Set: false}}
+
+	res, err := controller.Onboard(context.Background(), &req)
+
+	assert.NoError(t, err)
+	assert.IsType(t, &api.OnboardConflict{}, res)
+}
+
+func TestOnboardingController_Offboard_Success_NoGroup(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Username: "test-user",
+		Groups:   []string{"group1"},
+	})
+
+	mockUsecase.On("Offboard", mock.Anything, domain.OffboardingRequest{UserName: "test-user"}).
+		Return(nil)
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OffboardingRequest{Group: api.OptString{Set: false}}
+
+	res, err := controller.Offboard(context.Background(), &req)
+
+	assert.NoError(t, err)
+	assert.IsType(t, &api.OffboardOK{}, res)
+	mockUsecase.AssertCalled(
+		t,
+		"Offboard",
+		mock.Anything,
+		domain.OffboardingRequest{UserName: "test-user"},
+	)
+}
+
+func TestOnboardingController_Offboard_GetUserFails(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(nil) // ❌ GetUser fails
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OffboardingRequest{Group: api.OptString{Set: false}}
+
+	res, err := controller.Offboard(context.Background(), &req)
+
+	assert.Error(t, err)
+	assert.IsType(t, &api.OffboardForbidden{}, res)
+	mockUsecase.AssertNotCalled(t, "Offboard")
+}
+
+func TestOnboardingController_Offboard_GroupValidationFails(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Username: "test-user",
+		Groups:   []string{"other-group"}, // ❌ Does not match "test-group"
+	})
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OffboardingRequest{Group: api.OptString{Value: "test-group", Set: true}}
+
+	res, err := controller.Offboard(context.Background(), &req)
+
+	assert.Error(t, err)
+	assert.IsType(t, &api.OffboardUnauthorized{}, res)
+	mockUsecase.AssertNotCalled(t, "Offboard")
+}
+
+func TestOnboardingController_Offboard_OffboardingFails(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Username: "test-user",
+		Groups:   []string{"test-group"},
+	})
+
+	mockUsecase.On("Offboard", mock.Anything, mock.Anything).
+		Return(errors.New("offboarding is disabled"))
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OffboardingRequest{Group: api.OptString{Value: "test-group", Set: true}}
+
+	res, err := controller.Offboard(context.Background(), &req)
+
+	assert.Error(t, err)
+	assert.IsType(t, &api.OffboardForbidden{}, res)
+	mockUsecase.AssertCalled(t, "Offboard", mock.Anything, mock.Anything)
+}
+
+func TestOnboardingController_Offboard_NotOwned(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{Username: "test-user"})
+
+	mockUsecase.On("Offboard", mock.Anything, mock.Anything).
+		Return(fmt.Errorf("failed to offboard namespace (user-test-user): %w",
+			domain.ErrNamespaceNotOwned))
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OffboardingRequest{Group: api.OptString{Set: false}}
+
+	res, err := controller.Offboard(context.Background(), &req)
+
+	assert.NoError(t, err)
+	assert.IsType(t, &api.OffboardConflict{}, res)
+}
+
+func TestOnboardingController_GetOnboardingStatus_Success(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Username: "test-user",
+		Groups:   []string{"group1"},
+	})
+
+	group := "group1"
+	mockUsecase.On("Status", mock.Anything, domain.OnboardingRequest{
+		Group:      &group,
+		UserName:   "test-user",
+		UserGroups: []string{"group1"},
+	}).Return(domain.NamespaceStatus{
+		Name:   "projet-group1",
+		Exists: true,
+		Quota: &domain.QuotaStatus{
+			Hard: map[string]string{"requests.cpu": "4"},
+			Used: map[string]string{"requests.cpu": "1"},
+		},
+	}, nil)
+
+	controller := setupController(mockUsecase, mockUserCtx)
+
+	res, err := controller.GetOnboardingStatus(
+		context.Background(),
+		api.GetOnboardingStatusParams{Group: api.NewOptString("group1")},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &api.OnboardingStatus{
+		Namespace: "projet-group1",
+		Exists:    true,
+		Quota: api.NewOptQuotaStatus(api.QuotaStatus{
+			Hard: api.QuotaStatusHard{"requests.cpu": "4"},
+			Used: api.QuotaStatusUsed{"requests.cpu": "1"},
+		}),
+	}, res)
+}
+
+func TestOnboardingController_GetOnboardingStatus_GroupValidationFails(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Username: "test-user",
+		Groups:   []string{"group1"},
+	})
+
+	controller := setupController(mockUsecase, mockUserCtx)
+
+	res, err := controller.GetOnboardingStatus(
+		context.Background(),
+		api.GetOnboardingStatusParams{Group: api.NewOptString("unauthorized-group")},
+	)
+
+	assert.Error(t, err)
+	assert.IsType(t, &api.GetOnboardingStatusUnauthorized{}, res)
+	mockUsecase.AssertNotCalled(t, "Status", mock.Anything, mock.Anything)
+}
+
+func TestOnboardingController_GetOnboardingStatus_NotOwned(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{Username: "test-user"})
+
+	mockUsecase.On("Status", mock.Anything, mock.Anything).
+		Return(domain.NamespaceStatus{}, fmt.Errorf("%w: namespace user-test-user",
+			domain.ErrNamespaceNotOwned))
+
+	controller := setupController(mockUsecase, mockUserCtx)
+
+	res, err := controller.GetOnboardingStatus(
+		context.Background(),
+		api.GetOnboardingStatusParams{},
+	)
+
+	assert.NoError(t, err)
+	assert.IsType(t, &api.GetOnboardingStatusConflict{}, res)
+}
+
+func TestOnboardingController_Onboard_FieldManagerConflict(t *testing.T) {
+	mockUsecase := new(MockOnboardingUsecase)
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{Username: "test-user"})
+
+	mockUsecase.On("Onboard", mock.Anything, mock.Anything).
+		Return(domain.OnboardingResult{}, fmt.Errorf(
+			"%w: resource quota user-test-user/onyxia-quota",
+			domain.ErrFieldManagerConflict,
+		))
+
+	controller := setupController(mockUsecase, mockUserCtx)
+	req := api.OnboardingRequest{Group: api.OptString{Set: false}}
+
+	res, err := controller.Onboard(context.Background(), &req)
+
+	assert.NoError(t, err)
+	assert.IsType(t, &api.OnboardConflict{}, res)
+}
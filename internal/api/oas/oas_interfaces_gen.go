@@ -1,6 +1,14 @@
 // Code generated by ogen, DO NOT EDIT.
 package api
 
+type GetOnboardingStatusRes interface {
+	getOnboardingStatusRes()
+}
+
+type OffboardRes interface {
+	offboardRes()
+}
+
 type OnboardRes interface {
 	onboardRes()
 }
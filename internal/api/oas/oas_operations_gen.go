@@ -6,5 +6,7 @@ package api
 type OperationName = string
 
 const (
-	OnboardOperation OperationName = "Onboard"
+	GetOnboardingStatusOperation OperationName = "GetOnboardingStatus"
+	OffboardOperation            OperationName = "Offboard"
+	OnboardOperation             OperationName = "Onboard"
 )
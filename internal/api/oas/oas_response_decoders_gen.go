@@ -3,22 +3,131 @@
 package api
 
 import (
+	"io"
+	"mime"
 	"net/http"
 
+	"github.com/go-faster/errors"
+	"github.com/go-faster/jx"
+
+	"github.com/ogen-go/ogen/ogenerrors"
 	"github.com/ogen-go/ogen/validate"
 )
 
+func decodeGetOnboardingStatusResponse(resp *http.Response) (res GetOnboardingStatusRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response OnboardingStatus
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
+	case 401:
+		// Code 401.
+		return &GetOnboardingStatusUnauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &GetOnboardingStatusForbidden{}, nil
+	case 409:
+		// Code 409.
+		return &GetOnboardingStatusConflict{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
+func decodeOffboardResponse(resp *http.Response) (res OffboardRes, _ error) {
+	switch resp.StatusCode {
+	case 200:
+		// Code 200.
+		return &OffboardOK{}, nil
+	case 401:
+		// Code 401.
+		return &OffboardUnauthorized{}, nil
+	case 403:
+		// Code 403.
+		return &OffboardForbidden{}, nil
+	case 409:
+		// Code 409.
+		return &OffboardConflict{}, nil
+	}
+	return res, validate.UnexpectedStatusCode(resp.StatusCode)
+}
+
 func decodeOnboardResponse(resp *http.Response) (res OnboardRes, _ error) {
 	switch resp.StatusCode {
 	case 200:
 		// Code 200.
-		return &OnboardOK{}, nil
+		ct, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			return res, errors.Wrap(err, "parse media type")
+		}
+		switch {
+		case ct == "application/json":
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return res, err
+			}
+			d := jx.DecodeBytes(buf)
+
+			var response OnboardingResult
+			if err := func() error {
+				if err := response.Decode(d); err != nil {
+					return err
+				}
+				if err := d.Skip(); err != io.EOF {
+					return errors.New("unexpected trailing data")
+				}
+				return nil
+			}(); err != nil {
+				err = &ogenerrors.DecodeBodyError{
+					ContentType: ct,
+					Body:        buf,
+					Err:         err,
+				}
+				return res, err
+			}
+			return &response, nil
+		default:
+			return res, validate.InvalidContentType(ct)
+		}
 	case 401:
 		// Code 401.
 		return &OnboardUnauthorized{}, nil
 	case 403:
 		// Code 403.
 		return &OnboardForbidden{}, nil
+	case 409:
+		// Code 409.
+		return &OnboardConflict{}, nil
 	}
 	return res, validate.UnexpectedStatusCode(resp.StatusCode)
 }
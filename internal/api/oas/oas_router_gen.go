@@ -48,24 +48,60 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 		switch elem[0] {
-		case '/': // Prefix: "/onboarding"
+		case '/': // Prefix: "/o"
 
-			if l := len("/onboarding"); len(elem) >= l && elem[0:l] == "/onboarding" {
+			if l := len("/o"); len(elem) >= l && elem[0:l] == "/o" {
 				elem = elem[l:]
 			} else {
 				break
 			}
 
 			if len(elem) == 0 {
-				// Leaf node.
-				switch r.Method {
-				case "POST":
-					s.handleOnboardRequest([0]string{}, elemIsEscaped, w, r)
-				default:
-					s.notAllowed(w, r, "POST")
+				break
+			}
+			switch elem[0] {
+			case 'f': // Prefix: "ffboarding"
+
+				if l := len("ffboarding"); len(elem) >= l && elem[0:l] == "ffboarding" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					// Leaf node.
+					switch r.Method {
+					case "POST":
+						s.handleOffboardRequest([0]string{}, elemIsEscaped, w, r)
+					default:
+						s.notAllowed(w, r, "POST")
+					}
+
+					return
+				}
+
+			case 'n': // Prefix: "nboarding"
+
+				if l := len("nboarding"); len(elem) >= l && elem[0:l] == "nboarding" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					// Leaf node.
+					switch r.Method {
+					case "GET":
+						s.handleGetOnboardingStatusRequest([0]string{}, elemIsEscaped, w, r)
+					case "POST":
+						s.handleOnboardRequest([0]string{}, elemIsEscaped, w, r)
+					default:
+						s.notAllowed(w, r, "GET,POST")
+					}
+
+					return
 				}
 
-				return
 			}
 
 		}
@@ -148,28 +184,74 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 			break
 		}
 		switch elem[0] {
-		case '/': // Prefix: "/onboarding"
+		case '/': // Prefix: "/o"
 
-			if l := len("/onboarding"); len(elem) >= l && elem[0:l] == "/onboarding" {
+			if l := len("/o"); len(elem) >= l && elem[0:l] == "/o" {
 				elem = elem[l:]
 			} else {
 				break
 			}
 
 			if len(elem) == 0 {
-				// Leaf node.
-				switch method {
-				case "POST":
-					r.name = OnboardOperation
-					r.summary = "Init a user or a group"
-					r.operationID = "onboard"
-					r.pathPattern = "/onboarding"
-					r.args = args
-					r.count = 0
-					return r, true
-				default:
-					return
+				break
+			}
+			switch elem[0] {
+			case 'f': // Prefix: "ffboarding"
+
+				if l := len("ffboarding"); len(elem) >= l && elem[0:l] == "ffboarding" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					// Leaf node.
+					switch method {
+					case "POST":
+						r.name = OffboardOperation
+						r.summary = "Offboard a user or a group"
+						r.operationID = "offboard"
+						r.pathPattern = "/offboarding"
+						r.args = args
+						r.count = 0
+						return r, true
+					default:
+						return
+					}
 				}
+
+			case 'n': // Prefix: "nboarding"
+
+				if l := len("nboarding"); len(elem) >= l && elem[0:l] == "nboarding" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					// Leaf node.
+					switch method {
+					case "GET":
+						r.name = GetOnboardingStatusOperation
+						r.summary = "Get the namespace of a user or a group"
+						r.operationID = "getOnboardingStatus"
+						r.pathPattern = "/onboarding"
+						r.args = args
+						r.count = 0
+						return r, true
+					case "POST":
+						r.name = OnboardOperation
+						r.summary = "Init a user or a group"
+						r.operationID = "onboard"
+						r.pathPattern = "/onboarding"
+						r.args = args
+						r.count = 0
+						return r, true
+					default:
+						return
+					}
+				}
+
 			}
 
 		}
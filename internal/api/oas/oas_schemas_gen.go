@@ -2,6 +2,57 @@
 
 package api
 
+// GetOnboardingStatusConflict is response for GetOnboardingStatus operation.
+type GetOnboardingStatusConflict struct{}
+
+func (*GetOnboardingStatusConflict) getOnboardingStatusRes() {}
+
+// GetOnboardingStatusForbidden is response for GetOnboardingStatus operation.
+type GetOnboardingStatusForbidden struct{}
+
+func (*GetOnboardingStatusForbidden) getOnboardingStatusRes() {}
+
+// GetOnboardingStatusUnauthorized is response for GetOnboardingStatus operation.
+type GetOnboardingStatusUnauthorized struct{}
+
+func (*GetOnboardingStatusUnauthorized) getOnboardingStatusRes() {}
+
+// OffboardConflict is response for Offboard operation.
+type OffboardConflict struct{}
+
+func (*OffboardConflict) offboardRes() {}
+
+// OffboardForbidden is response for Offboard operation.
+type OffboardForbidden struct{}
+
+func (*OffboardForbidden) offboardRes() {}
+
+// OffboardOK is response for Offboard operation.
+type OffboardOK struct{}
+
+func (*OffboardOK) offboardRes() {}
+
+// OffboardUnauthorized is response for Offboard operation.
+type OffboardUnauthorized struct{}
+
+func (*OffboardUnauthorized) offboardRes() {}
+
+// Specification on which namespace to offboard.
+// Ref: #/components/schemas/OffboardingRequest
+type OffboardingRequest struct {
+	Group OptString `json:"group"`
+}
+
+// GetGroup returns the value of Group.
+func (s *OffboardingRequest) GetGroup() OptString {
+	return s.Group
+}
+
+// SetGroup sets the value of Group.
+func (s *OffboardingRequest) SetGroup(val OptString) {
+	s.Group = val
+}
+
 type Oidc struct {
 	Token  string
 	Scopes []string
@@ -27,16 +78,16 @@ func (s *Oidc) SetScopes(val []string) {
 	s.Scopes = val
 }
 
+// OnboardConflict is response for Onboard operation.
+type OnboardConflict struct{}
+
+func (*OnboardConflict) onboardRes() {}
+
 // OnboardForbidden is response for Onboard operation.
 type OnboardForbidden struct{}
 
 func (*OnboardForbidden) onboardRes() {}
 
-// OnboardOK is response for Onboard operation.
-type OnboardOK struct{}
-
-func (*OnboardOK) onboardRes() {}
-
 // OnboardUnauthorized is response for Onboard operation.
 type OnboardUnauthorized struct{}
 
@@ -46,6 +97,8 @@ func (*OnboardUnauthorized) onboardRes() {}
 // Ref: #/components/schemas/OnboardingRequest
 type OnboardingRequest struct {
 	Group OptString `json:"group"`
+	// Compute what onboarding would do without persisting anything.
+	DryRun OptBool `json:"dryRun"`
 }
 
 // GetGroup returns the value of Group.
@@ -53,11 +106,483 @@ func (s *OnboardingRequest) GetGroup() OptString {
 	return s.Group
 }
 
+// GetDryRun returns the value of DryRun.
+func (s *OnboardingRequest) GetDryRun() OptBool {
+	return s.DryRun
+}
+
 // SetGroup sets the value of Group.
 func (s *OnboardingRequest) SetGroup(val OptString) {
 	s.Group = val
 }
 
+// SetDryRun sets the value of DryRun.
+func (s *OnboardingRequest) SetDryRun(val OptBool) {
+	s.DryRun = val
+}
+
+// Namespace computed for the request.
+// Ref: #/components/schemas/OnboardingResult
+type OnboardingResult struct {
+	Namespace   string                         `json:"namespace"`
+	Annotations OptOnboardingResultAnnotations `json:"annotations"`
+	Labels      OptOnboardingResultLabels      `json:"labels"`
+	// Quota profile selected for the namespace, empty when quotas are disabled.
+	QuotaProfile OptString `json:"quotaProfile"`
+	DryRun       bool      `json:"dryRun"`
+	// True when the namespace was onboarded recently with the same desired state, in which case
+	// nothing was applied.
+	Skipped bool `json:"skipped"`
+}
+
+// GetNamespace returns the value of Namespace.
+func (s *OnboardingResult) GetNamespace() string {
+	return s.Namespace
+}
+
+// GetAnnotations returns the value of Annotations.
+func (s *OnboardingResult) GetAnnotations() OptOnboardingResultAnnotations {
+	return s.Annotations
+}
+
+// GetLabels returns the value of Labels.
+func (s *OnboardingResult) GetLabels() OptOnboardingResultLabels {
+	return s.Labels
+}
+
+// GetQuotaProfile returns the value of QuotaProfile.
+func (s *OnboardingResult) GetQuotaProfile() OptString {
+	return s.QuotaProfile
+}
+
+// GetDryRun returns the value of DryRun.
+func (s *OnboardingResult) GetDryRun() bool {
+	return s.DryRun
+}
+
+// GetSkipped returns the value of Skipped.
+func (s *OnboardingResult) GetSkipped() bool {
+	return s.Skipped
+}
+
+// SetNamespace sets the value of Namespace.
+func (s *OnboardingResult) SetNamespace(val string) {
+	s.Namespace = val
+}
+
+// SetAnnotations sets the value of Annotations.
+func (s *OnboardingResult) SetAnnotations(val OptOnboardingResultAnnotations) {
+	s.Annotations = val
+}
+
+// SetLabels sets the value of Labels.
+func (s *OnboardingResult) SetLabels(val OptOnboardingResultLabels) {
+	s.Labels = val
+}
+
+// SetQuotaProfile sets the value of QuotaProfile.
+func (s *OnboardingResult) SetQuotaProfile(val OptString) {
+	s.QuotaProfile = val
+}
+
+// SetDryRun sets the value of DryRun.
+func (s *OnboardingResult) SetDryRun(val bool) {
+	s.DryRun = val
+}
+
+// SetSkipped sets the value of Skipped.
+func (s *OnboardingResult) SetSkipped(val bool) {
+	s.Skipped = val
+}
+
+func (*OnboardingResult) onboardRes() {}
+
+type OnboardingResultAnnotations map[string]string
+
+func (s *OnboardingResultAnnotations) init() OnboardingResultAnnotations {
+	m := *s
+	if m == nil {
+		m = map[string]string{}
+		*s = m
+	}
+	return m
+}
+
+type OnboardingResultLabels map[string]string
+
+func (s *OnboardingResultLabels) init() OnboardingResultLabels {
+	m := *s
+	if m == nil {
+		m = map[string]string{}
+		*s = m
+	}
+	return m
+}
+
+// Namespace of a user or a group.
+// Ref: #/components/schemas/OnboardingStatus
+type OnboardingStatus struct {
+	Namespace string `json:"namespace"`
+	Exists    bool   `json:"exists"`
+	// Annotations of the namespace managed by the onboarding.
+	Annotations OptOnboardingStatusAnnotations `json:"annotations"`
+	// Labels of the namespace managed by the onboarding.
+	Labels OptOnboardingStatusLabels `json:"labels"`
+	Quota  OptQuotaStatus            `json:"quota"`
+}
+
+// GetNamespace returns the value of Namespace.
+func (s *OnboardingStatus) GetNamespace() string {
+	return s.Namespace
+}
+
+// GetExists returns the value of Exists.
+func (s *OnboardingStatus) GetExists() bool {
+	return s.Exists
+}
+
+// GetAnnotations returns the value of Annotations.
+func (s *OnboardingStatus) GetAnnotations() OptOnboardingStatusAnnotations {
+	return s.Annotations
+}
+
+// GetLabels returns the value of Labels.
+func (s *OnboardingStatus) GetLabels() OptOnboardingStatusLabels {
+	return s.Labels
+}
+
+// GetQuota returns the value of Quota.
+func (s *OnboardingStatus) GetQuota() OptQuotaStatus {
+	return s.Quota
+}
+
+// SetNamespace sets the value of Namespace.
+func (s *OnboardingStatus) SetNamespace(val string) {
+	s.Namespace = val
+}
+
+// SetExists sets the value of Exists.
+func (s *OnboardingStatus) SetExists(val bool) {
+	s.Exists = val
+}
+
+// SetAnnotations sets the value of Annotations.
+func (s *OnboardingStatus) SetAnnotations(val OptOnboardingStatusAnnotations) {
+	s.Annotations = val
+}
+
+// SetLabels sets the value of Labels.
+func (s *OnboardingStatus) SetLabels(val OptOnboardingStatusLabels) {
+	s.Labels = val
+}
+
+// SetQuota sets the value of Quota.
+func (s *OnboardingStatus) SetQuota(val OptQuotaStatus) {
+	s.Quota = val
+}
+
+func (*OnboardingStatus) getOnboardingStatusRes() {}
+
+// Annotations of the namespace managed by the onboarding.
+type OnboardingStatusAnnotations map[string]string
+
+func (s *OnboardingStatusAnnotations) init() OnboardingStatusAnnotations {
+	m := *s
+	if m == nil {
+		m = map[string]string{}
+		*s = m
+	}
+	return m
+}
+
+// Labels of the namespace managed by the onboarding.
+type OnboardingStatusLabels map[string]string
+
+func (s *OnboardingStatusLabels) init() OnboardingStatusLabels {
+	m := *s
+	if m == nil {
+		m = map[string]string{}
+		*s = m
+	}
+	return m
+}
+
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
+	Set   bool
+}
+
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptOnboardingResultAnnotations returns new OptOnboardingResultAnnotations with value set to v.
+func NewOptOnboardingResultAnnotations(v OnboardingResultAnnotations) OptOnboardingResultAnnotations {
+	return OptOnboardingResultAnnotations{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptOnboardingResultAnnotations is optional OnboardingResultAnnotations.
+type OptOnboardingResultAnnotations struct {
+	Value OnboardingResultAnnotations
+	Set   bool
+}
+
+// IsSet returns true if OptOnboardingResultAnnotations was set.
+func (o OptOnboardingResultAnnotations) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptOnboardingResultAnnotations) Reset() {
+	var v OnboardingResultAnnotations
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptOnboardingResultAnnotations) SetTo(v OnboardingResultAnnotations) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptOnboardingResultAnnotations) Get() (v OnboardingResultAnnotations, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptOnboardingResultAnnotations) Or(d OnboardingResultAnnotations) OnboardingResultAnnotations {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptOnboardingResultLabels returns new OptOnboardingResultLabels with value set to v.
+func NewOptOnboardingResultLabels(v OnboardingResultLabels) OptOnboardingResultLabels {
+	return OptOnboardingResultLabels{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptOnboardingResultLabels is optional OnboardingResultLabels.
+type OptOnboardingResultLabels struct {
+	Value OnboardingResultLabels
+	Set   bool
+}
+
+// IsSet returns true if OptOnboardingResultLabels was set.
+func (o OptOnboardingResultLabels) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptOnboardingResultLabels) Reset() {
+	var v OnboardingResultLabels
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptOnboardingResultLabels) SetTo(v OnboardingResultLabels) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptOnboardingResultLabels) Get() (v OnboardingResultLabels, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptOnboardingResultLabels) Or(d OnboardingResultLabels) OnboardingResultLabels {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptOnboardingStatusAnnotations returns new OptOnboardingStatusAnnotations with value set to v.
+func NewOptOnboardingStatusAnnotations(v OnboardingStatusAnnotations) OptOnboardingStatusAnnotations {
+	return OptOnboardingStatusAnnotations{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptOnboardingStatusAnnotations is optional OnboardingStatusAnnotations.
+type OptOnboardingStatusAnnotations struct {
+	Value OnboardingStatusAnnotations
+	Set   bool
+}
+
+// IsSet returns true if OptOnboardingStatusAnnotations was set.
+func (o OptOnboardingStatusAnnotations) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptOnboardingStatusAnnotations) Reset() {
+	var v OnboardingStatusAnnotations
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptOnboardingStatusAnnotations) SetTo(v OnboardingStatusAnnotations) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptOnboardingStatusAnnotations) Get() (v OnboardingStatusAnnotations, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptOnboardingStatusAnnotations) Or(d OnboardingStatusAnnotations) OnboardingStatusAnnotations {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptOnboardingStatusLabels returns new OptOnboardingStatusLabels with value set to v.
+func NewOptOnboardingStatusLabels(v OnboardingStatusLabels) OptOnboardingStatusLabels {
+	return OptOnboardingStatusLabels{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptOnboardingStatusLabels is optional OnboardingStatusLabels.
+type OptOnboardingStatusLabels struct {
+	Value OnboardingStatusLabels
+	Set   bool
+}
+
+// IsSet returns true if OptOnboardingStatusLabels was set.
+func (o OptOnboardingStatusLabels) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptOnboardingStatusLabels) Reset() {
+	var v OnboardingStatusLabels
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptOnboardingStatusLabels) SetTo(v OnboardingStatusLabels) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptOnboardingStatusLabels) Get() (v OnboardingStatusLabels, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptOnboardingStatusLabels) Or(d OnboardingStatusLabels) OnboardingStatusLabels {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptQuotaStatus returns new OptQuotaStatus with value set to v.
+func NewOptQuotaStatus(v QuotaStatus) OptQuotaStatus {
+	return OptQuotaStatus{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptQuotaStatus is optional QuotaStatus.
+type OptQuotaStatus struct {
+	Value QuotaStatus
+	Set   bool
+}
+
+// IsSet returns true if OptQuotaStatus was set.
+func (o OptQuotaStatus) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptQuotaStatus) Reset() {
+	var v QuotaStatus
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptQuotaStatus) SetTo(v QuotaStatus) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptQuotaStatus) Get() (v QuotaStatus, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptQuotaStatus) Or(d QuotaStatus) QuotaStatus {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptString returns new OptString with value set to v.
 func NewOptString(v string) OptString {
 	return OptString{
@@ -103,3 +628,69 @@ func (o OptString) Or(d string) string {
 	}
 	return d
 }
+
+// The onyxia-quota of a namespace.
+// Ref: #/components/schemas/QuotaStatus
+type QuotaStatus struct {
+	// Limits of the onyxia-quota.
+	Hard QuotaStatusHard `json:"hard"`
+	// Current usage of the namespace.
+	Used QuotaStatusUsed `json:"used"`
+	// Whether the quota is marked with the onyxia.sh/ignore annotation and left untouched by the
+	// onboarding.
+	Ignored bool `json:"ignored"`
+}
+
+// GetHard returns the value of Hard.
+func (s *QuotaStatus) GetHard() QuotaStatusHard {
+	return s.Hard
+}
+
+// GetUsed returns the value of Used.
+func (s *QuotaStatus) GetUsed() QuotaStatusUsed {
+	return s.Used
+}
+
+// GetIgnored returns the value of Ignored.
+func (s *QuotaStatus) GetIgnored() bool {
+	return s.Ignored
+}
+
+// SetHard sets the value of Hard.
+func (s *QuotaStatus) SetHard(val QuotaStatusHard) {
+	s.Hard = val
+}
+
+// SetUsed sets the value of Used.
+func (s *QuotaStatus) SetUsed(val QuotaStatusUsed) {
+	s.Used = val
+}
+
+// SetIgnored sets the value of Ignored.
+func (s *QuotaStatus) SetIgnored(val bool) {
+	s.Ignored = val
+}
+
+// Limits of the onyxia-quota.
+type QuotaStatusHard map[string]string
+
+func (s *QuotaStatusHard) init() QuotaStatusHard {
+	m := *s
+	if m == nil {
+		m = map[string]string{}
+		*s = m
+	}
+	return m
+}
+
+// Current usage of the namespace.
+type QuotaStatusUsed map[string]string
+
+func (s *QuotaStatusUsed) init() QuotaStatusUsed {
+	m := *s
+	if m == nil {
+		m = map[string]string{}
+		*s = m
+	}
+	return m
+}
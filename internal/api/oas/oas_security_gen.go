@@ -34,7 +34,9 @@ func findAuthorization(h http.Header, prefix string) (string, bool) {
 }
 
 var oauth2ScopesOidc = map[string][]string{
-	OnboardOperation: {},
+	GetOnboardingStatusOperation: {},
+	OffboardOperation:            {},
+	OnboardOperation:             {},
 }
 
 func (s *Server) securityOidc(ctx context.Context, operationName OperationName, req *http.Request) (context.Context, bool, error) {
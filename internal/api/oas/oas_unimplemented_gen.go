@@ -13,6 +13,32 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// GetOnboardingStatus implements getOnboardingStatus operation.
+//
+// This endpoint returns the namespace the caller (or one of their groups) is onboarded in: whether
+// it exists, the annotations and labels managed by the onboarding, and the onyxia-quota with its
+// current usage. Nothing is created or modified.
+//
+// GET /onboarding
+func (UnimplementedHandler) GetOnboardingStatus(ctx context.Context, params GetOnboardingStatusParams) (r GetOnboardingStatusRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// Offboard implements offboard operation.
+//
+// This endpoint tears down the namespace of a user or a group. Depending on the region configuration,
+//
+//	the namespace is either deleted right away or archived: it is labelled as archived, its quota is
+//
+// set to zero so no workload can run anymore, and its data is kept until the grace period expires.
+// Offboarding an archived namespace whose grace period has expired deletes it. This behavior is
+// disabled by default.
+//
+// POST /offboarding
+func (UnimplementedHandler) Offboard(ctx context.Context, req *OffboardingRequest) (r OffboardRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // Onboard implements onboard operation.
 //
 // This endpoint manages all tasks performed when a user logs into the region. It handles the
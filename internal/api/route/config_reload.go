@@ -0,0 +1,64 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/usecase"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// newConfigReloadCounter returns the counter of configuration reloads, from the global
+// MeterProvider set up with the metrics.
+func newConfigReloadCounter() (metric.Int64Counter, error) {
+	counter, err := otel.Meter("github.com/onyxia-datalab/onyxia-onboarding").Int64Counter(
+		"onboarding.config.reloads",
+		metric.WithDescription("Configuration reloads, by result (accepted or rejected)"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create configuration reload counter: %w", err)
+	}
+	return counter, nil
+}
+
+// reloadOnboardingConfig swaps the onboarding usecase for one built from the reloaded
+// configuration. An invalid configuration is rejected and the current one is kept.
+func reloadOnboardingConfig(
+	app *bootstrap.Application,
+	reloadable *usecase.ReloadableOnboardingUsecase,
+	reloads metric.Int64Counter,
+	env *bootstrap.Env,
+	err error,
+) bool {
+	if err == nil {
+		var onboardingUsecase domain.OnboardingUsecase
+		if onboardingUsecase, err = newOnboardingUsecase(app, env.Onboarding); err == nil {
+			reloadable.Reload(onboardingUsecase)
+		}
+	}
+
+	if err != nil {
+		slog.Error("❌ Configuration reload rejected, keeping the current configuration",
+			slog.Any("error", err),
+		)
+		countConfigReload(reloads, "rejected")
+		return false
+	}
+
+	slog.Info("✅ Configuration reloaded")
+	countConfigReload(reloads, "accepted")
+	return true
+}
+
+func countConfigReload(reloads metric.Int64Counter, result string) {
+	reloads.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(attribute.String("result", result)),
+	)
+}
@@ -0,0 +1,61 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/kubernetes"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestNewConfigReloadCounter(t *testing.T) {
+	counter, err := newConfigReloadCounter()
+
+	assert.NoError(t, err)
+	assert.NotNil(t, counter)
+}
+
+func TestReloadOnboardingConfig(t *testing.T) {
+	app := &bootstrap.Application{K8sClient: &kubernetes.KubernetesClient{}}
+	reloadable := usecase.NewReloadableOnboardingUsecase(nil)
+
+	env, err := bootstrap.NewEnv()
+	assert.NoError(t, err)
+
+	assert.True(t, reloadOnboardingConfig(app, reloadable, noop.Int64Counter{}, env, nil))
+}
+
+func TestReloadOnboardingConfig_Rejected(t *testing.T) {
+	app := &bootstrap.Application{K8sClient: &kubernetes.KubernetesClient{}}
+	reloadable := usecase.NewReloadableOnboardingUsecase(nil)
+
+	reader := sdkmetric.NewManualReader()
+	reloads, err := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).
+		Meter("test").
+		Int64Counter("onboarding.config.reloads")
+	assert.NoError(t, err)
+
+	env, err := bootstrap.NewEnv()
+	assert.NoError(t, err)
+	env.Onboarding.Quotas.RoleMergeStrategy = "min"
+
+	// ✅ An invalid configuration is not applied
+	assert.False(t, reloadOnboardingConfig(app, reloadable, reloads, env, nil))
+	assert.False(t,
+		reloadOnboardingConfig(app, reloadable, reloads, nil, errors.New("invalid yaml")))
+
+	// ✅ Rejections are counted
+	var metrics metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &metrics))
+	sum := metrics.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	assert.Equal(t, int64(2), sum.DataPoints[0].Value)
+	result, _ := sum.DataPoints[0].Attributes.Value("result")
+	assert.Equal(t, attribute.StringValue("rejected"), result)
+}
@@ -8,7 +8,12 @@ import (
 
 type MyHandler struct {
 	oas.UnimplementedHandler
-	onboardImpl func(ctx context.Context, req *oas.OnboardingRequest) (oas.OnboardRes, error)
+	onboardImpl  func(ctx context.Context, req *oas.OnboardingRequest) (oas.OnboardRes, error)
+	offboardImpl func(ctx context.Context, req *oas.OffboardingRequest) (oas.OffboardRes, error)
+	statusImpl   func(
+		ctx context.Context,
+		params oas.GetOnboardingStatusParams,
+	) (oas.GetOnboardingStatusRes, error)
 }
 
 func (h *MyHandler) Onboard(
@@ -18,4 +23,18 @@ func (h *MyHandler) Onboard(
 	return h.onboardImpl(ctx, req)
 }
 
+func (h *MyHandler) Offboard(
+	ctx context.Context,
+	req *oas.OffboardingRequest,
+) (oas.OffboardRes, error) {
+	return h.offboardImpl(ctx, req)
+}
+
+func (h *MyHandler) GetOnboardingStatus(
+	ctx context.Context,
+	params oas.GetOnboardingStatusParams,
+) (oas.GetOnboardingStatusRes, error) {
+	return h.statusImpl(ctx, params)
+}
+
 var _ oas.Handler = (*MyHandler)(nil)
@@ -1,19 +1,63 @@
 package route
 
 import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/api/controller"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/kubernetes"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/usecase"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func SetupOnboardingController(
 	app *bootstrap.Application,
-) *controller.OnboardingController {
-	namespaceCreator := kubernetes.NewKubernetesNamespaceService(app.K8sClient.Clientset)
+) (*controller.OnboardingController, error) {
+	onboardingUsecase, err := newOnboardingUsecase(app, app.Env.Onboarding)
+	if err != nil {
+		return nil, err
+	}
+
+	reloadable := usecase.NewReloadableOnboardingUsecase(onboardingUsecase)
+	if app.Env.WatchConfig {
+		reloads, err := newConfigReloadCounter()
+		if err != nil {
+			return nil, err
+		}
+		bootstrap.WatchEnv(func(env *bootstrap.Env, err error) {
+			reloadOnboardingConfig(app, reloadable, reloads, env, err)
+		})
+	}
 
-	envQuotas := app.Env.Onboarding.Quotas
+	return controller.NewOnboardingController(reloadable, app.UserContextReader), nil
+}
+
+// newOnboardingUsecase validates the onboarding configuration and builds the usecase applying it.
+func newOnboardingUsecase(
+	app *bootstrap.Application,
+	onboarding bootstrap.Onboarding,
+) (domain.OnboardingUsecase, error) {
+	namespaceCreator := kubernetes.NewCachedKubernetesNamespaceService(
+		app.K8sClient.Clientset,
+		app.NamespaceCache,
+		onboarding.LegacyFieldManagers,
+	)
+	manifestService := kubernetes.NewKubernetesManifestService(
+		app.K8sClient.Clientset,
+		app.K8sClient.DynamicClient,
+		app.K8sClient.RESTMapper,
+	)
+
+	envQuotas := onboarding.Quotas
 
 	rolesDomainQuotas := func() map[string]domain.Quota {
 		result := make(map[string]domain.Quota)
@@ -23,35 +67,205 @@ func SetupOnboardingController(
 		return result
 	}()
 
+	groupsDomainQuotas, err := convertBootstrapGroupQuotasToDomain(envQuotas.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	roleMergeStrategy, err := convertBootstrapRoleMergeStrategyToDomain(envQuotas)
+	if err != nil {
+		return nil, err
+	}
+
+	removalPolicy, err := convertBootstrapQuotaRemovalPolicyToDomain(envQuotas.RemovalPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	offboarding, err := convertBootstrapOffboardingToDomain(onboarding.Offboarding)
+	if err != nil {
+		return nil, err
+	}
+
+	rbac, err := convertBootstrapRBACToDomain(onboarding.RBAC)
+	if err != nil {
+		return nil, err
+	}
+
+	networkPolicies, err := convertBootstrapNetworkPoliciesToDomain(
+		onboarding.NetworkPolicies,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := loadManifests(onboarding.Manifests, onboarding.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	nameTemplate, err := parseNamespaceTemplate(
+		"namespaceTemplate",
+		onboarding.NamespaceTemplate,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	groupNameTemplate, err := parseNamespaceTemplate(
+		"groupNamespaceTemplate",
+		onboarding.GroupNamespaceTemplate,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateReservedNamespaces(onboarding.ReservedNamespaces); err != nil {
+		return nil, err
+	}
+
+	if err := validateSkipIfOnboardedWithin(
+		onboarding.SkipIfOnboardedWithin,
+		onboarding.Annotation,
+	); err != nil {
+		return nil, err
+	}
+
+	dynamicLabels, err := convertBootstrapDynamicLabelsToDomain(
+		onboarding.NamespaceDynamicLabels,
+		onboarding.NamespaceLabels,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := domain.Quotas{
+		Enabled:           envQuotas.Enabled,
+		Default:           convertBootstrapQuotaToDomain(envQuotas.Default),
+		UserEnabled:       envQuotas.UserEnabled,
+		User:              convertBootstrapQuotaToDomain(envQuotas.User),
+		Roles:             rolesDomainQuotas,
+		GroupEnabled:      envQuotas.GroupEnabled,
+		Group:             convertBootstrapQuotaToDomain(envQuotas.Group),
+		Groups:            groupsDomainQuotas,
+		RoleMergeStrategy: roleMergeStrategy,
+		RolePriority:      envQuotas.RolePriority,
+		RemovalPolicy:     removalPolicy,
+	}
+	if err := validateQuotas(quotas); err != nil {
+		return nil, err
+	}
+
 	onboardingUsecase := usecase.NewOnboardingUsecase(
 		namespaceCreator,
+		manifestService,
 		domain.Namespace{
-			NamespacePrefix:      app.Env.Onboarding.NamespacePrefix,
-			GroupNamespacePrefix: app.Env.Onboarding.GroupNamespacePrefix,
-			NamespaceLabels:      app.Env.Onboarding.NamespaceLabels,
+			NamespacePrefix:       onboarding.NamespacePrefix,
+			GroupNamespacePrefix:  onboarding.GroupNamespacePrefix,
+			NameTemplate:          nameTemplate,
+			GroupNameTemplate:     groupNameTemplate,
+			NamespaceLabels:       onboarding.NamespaceLabels,
+			DynamicLabels:         dynamicLabels,
+			ReservedNames:         onboarding.ReservedNamespaces,
+			SkipIfOnboardedWithin: onboarding.SkipIfOnboardedWithin,
 			Annotation: domain.Annotation{
-				Enabled: app.Env.Onboarding.Annotation.Enabled,
-				Static:  app.Env.Onboarding.Annotation.Static,
+				Enabled: onboarding.Annotation.Enabled,
+				Static:  onboarding.Annotation.Static,
 				Dynamic: struct {
 					LastLoginTimestamp bool
 					UserAttributes     []string
-				}(app.Env.Onboarding.Annotation.Dynamic),
+				}(onboarding.Annotation.Dynamic),
 			},
 		},
-		domain.Quotas{
-			Enabled:      envQuotas.Enabled,
-			Default:      convertBootstrapQuotaToDomain(envQuotas.Default),
-			UserEnabled:  envQuotas.UserEnabled,
-			User:         convertBootstrapQuotaToDomain(envQuotas.User),
-			Roles:        rolesDomainQuotas,
-			GroupEnabled: envQuotas.GroupEnabled,
-			Group:        convertBootstrapQuotaToDomain(envQuotas.Group),
-		},
+		quotas,
+		offboarding,
+		rbac,
+		networkPolicies,
+		manifests,
+		onboarding.DryRun,
 		app.UserContextReader,
 	)
 
-	return controller.NewOnboardingController(onboardingUsecase, app.UserContextReader)
+	return onboardingUsecase, nil
+}
+
+// validateReservedNamespaces checks that reserved namespaces are valid glob patterns.
+func validateReservedNamespaces(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid reserved namespace pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// validateSkipIfOnboardedWithin checks that skipping recent onboardings can tell when the owner of
+// a namespace last logged in.
+func validateSkipIfOnboardedWithin(
+	skipIfOnboardedWithin time.Duration,
+	annotation bootstrap.Annotation,
+) error {
+	if skipIfOnboardedWithin < 0 {
+		return fmt.Errorf("invalid skipIfOnboardedWithin %s: must not be negative",
+			skipIfOnboardedWithin)
+	}
+	if skipIfOnboardedWithin > 0 &&
+		(!annotation.Enabled || !annotation.Dynamic.LastLoginTimestamp) {
+		return fmt.Errorf(
+			"skipIfOnboardedWithin %s requires annotations.dynamic.last-login-timestamp",
+			skipIfOnboardedWithin,
+		)
+	}
+	return nil
+}
+
+// parseNamespaceTemplate returns nil when no template is configured, in which case the namespace
+// prefix is used.
+func parseNamespaceTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := usecase.ParseNamespaceTemplate(name, text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", name, text, err)
+	}
 
+	return tmpl, nil
+}
+
+func convertBootstrapDynamicLabelsToDomain(
+	labels []bootstrap.DynamicLabel,
+	static map[string]string,
+) ([]domain.DynamicLabel, error) {
+	result := make([]domain.DynamicLabel, 0, len(labels))
+
+	for _, label := range labels {
+		if errs := validation.IsQualifiedName(label.Name); len(errs) > 0 {
+			return nil, fmt.Errorf(
+				"invalid dynamic label name %q: %s",
+				label.Name,
+				strings.Join(errs, ", "),
+			)
+		}
+		if _, exists := static[label.Name]; exists {
+			return nil, fmt.Errorf("dynamic label %q is also a static label", label.Name)
+		}
+		if slices.ContainsFunc(result, func(l domain.DynamicLabel) bool {
+			return l.Name == label.Name
+		}) {
+			return nil, fmt.Errorf("dynamic label %q is set twice", label.Name)
+		}
+
+		tmpl, err := usecase.ParseNamespaceTemplate(label.Name, label.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of dynamic label %q: %w", label.Name, err)
+		}
+
+		result = append(result, domain.DynamicLabel{Name: label.Name, Template: tmpl})
+	}
+
+	return result, nil
 }
 
 func convertBootstrapQuotaToDomain(q bootstrap.Quota) domain.Quota {
@@ -66,5 +280,284 @@ func convertBootstrapQuotaToDomain(q bootstrap.Quota) domain.Quota {
 		EphemeralStorageLimit:   q.LimitsEphemeralStorage,
 		GPURequest:              q.RequestsGPU,
 		GPULimit:                q.LimitsGPU,
+		Resources:               convertBootstrapQuotaResourcesToDomain(q.Resources),
+		Scoped:                  convertBootstrapScopedQuotasToDomain(q.Scoped),
+		LimitRange:              domain.LimitRange(q.LimitRange),
+	}
+}
+
+func convertBootstrapQuotaResourcesToDomain(resources []bootstrap.QuotaResource) map[string]string {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(resources))
+	for _, r := range resources {
+		result[r.Name] = r.Quantity
+	}
+	return result
+}
+
+func convertBootstrapScopedQuotasToDomain(scoped []bootstrap.ScopedQuota) []domain.ScopedQuota {
+	if len(scoped) == 0 {
+		return nil
+	}
+
+	result := make([]domain.ScopedQuota, 0, len(scoped))
+	for _, q := range scoped {
+		scopedQuota := domain.ScopedQuota{
+			Name:   q.Name,
+			Scopes: q.Scopes,
+			Hard:   convertBootstrapQuotaResourcesToDomain(q.Resources),
+		}
+		for _, r := range q.ScopeSelector {
+			scopedQuota.ScopeSelector = append(
+				scopedQuota.ScopeSelector,
+				domain.ScopeSelectorRequirement(r),
+			)
+		}
+		result = append(result, scopedQuota)
+	}
+	return result
+}
+
+// validateQuotas checks every quota profile at startup rather than on the first onboarding.
+func validateQuotas(quotas domain.Quotas) error {
+	if !quotas.Enabled {
+		return nil
+	}
+
+	profiles := map[string]domain.Quota{
+		"default": quotas.Default,
+		"user":    quotas.User,
+		"group":   quotas.Group,
+	}
+	for role, quota := range quotas.Roles {
+		profiles["roles."+role] = quota
+	}
+	for i, groupQuota := range quotas.Groups {
+		profiles[fmt.Sprintf("groups[%d]", i)] = groupQuota.Quota
+	}
+
+	for profile, quota := range profiles {
+		if err := kubernetes.ValidateQuota(quota); err != nil {
+			return fmt.Errorf("invalid %s quota: %w", profile, err)
+		}
+	}
+	return nil
+}
+
+func convertBootstrapGroupQuotasToDomain(
+	groups []bootstrap.GroupQuota,
+) ([]domain.GroupQuota, error) {
+	result := make([]domain.GroupQuota, 0, len(groups))
+
+	for i, g := range groups {
+		set := 0
+		for _, value := range []string{g.Group, g.Pattern, g.Regex} {
+			if value != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return nil, fmt.Errorf(
+				"group quota %d must set exactly one of group, pattern or regex",
+				i,
+			)
+		}
+
+		groupQuota := domain.GroupQuota{
+			Name:    g.Group,
+			Pattern: g.Pattern,
+			Quota:   convertBootstrapQuotaToDomain(g.Quota),
+		}
+
+		if g.Pattern != "" {
+			if _, err := path.Match(g.Pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid group quota pattern %q: %w", g.Pattern, err)
+			}
+		}
+
+		if g.Regex != "" {
+			regex, err := regexp.Compile(g.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid group quota regex %q: %w", g.Regex, err)
+			}
+			groupQuota.Regex = regex
+		}
+
+		result = append(result, groupQuota)
+	}
+
+	return result, nil
+}
+
+func convertBootstrapRoleMergeStrategyToDomain(
+	q bootstrap.Quotas,
+) (domain.RoleMergeStrategy, error) {
+	strategy := domain.RoleMergeStrategy(q.RoleMergeStrategy)
+
+	switch strategy {
+	case "":
+		return domain.RoleMergeStrategyFirst, nil
+	case domain.RoleMergeStrategyFirst, domain.RoleMergeStrategyMax, domain.RoleMergeStrategySum:
+		return strategy, nil
+	case domain.RoleMergeStrategyPriority:
+		for _, role := range q.RolePriority {
+			if _, exists := q.Roles[role]; !exists {
+				return "", fmt.Errorf("role %q of rolePriority has no quota in roles", role)
+			}
+		}
+		return strategy, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid role merge strategy %q, expected %q, %q, %q or %q",
+			q.RoleMergeStrategy,
+			domain.RoleMergeStrategyFirst,
+			domain.RoleMergeStrategyPriority,
+			domain.RoleMergeStrategyMax,
+			domain.RoleMergeStrategySum,
+		)
+	}
+}
+
+func convertBootstrapQuotaRemovalPolicyToDomain(p string) (domain.QuotaRemovalPolicy, error) {
+	policy := domain.QuotaRemovalPolicy(p)
+
+	switch policy {
+	case "":
+		return domain.QuotaRemovalPolicyKeep, nil
+	case domain.QuotaRemovalPolicyKeep, domain.QuotaRemovalPolicyDelete:
+		return policy, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid quota removal policy %q, expected %q or %q",
+			p,
+			domain.QuotaRemovalPolicyKeep,
+			domain.QuotaRemovalPolicyDelete,
+		)
+	}
+}
+
+func convertBootstrapOffboardingToDomain(o bootstrap.Offboarding) (domain.Offboarding, error) {
+	mode := domain.OffboardingMode(o.Mode)
+
+	if mode != domain.OffboardingModeDelete && mode != domain.OffboardingModeArchive {
+		return domain.Offboarding{}, fmt.Errorf(
+			"invalid offboarding mode %q, expected %q or %q",
+			o.Mode,
+			domain.OffboardingModeDelete,
+			domain.OffboardingModeArchive,
+		)
+	}
+
+	return domain.Offboarding{
+		Enabled:     o.Enabled,
+		Mode:        mode,
+		GracePeriod: o.GracePeriod,
+	}, nil
+}
+
+func convertBootstrapRBACToDomain(r bootstrap.RBAC) (domain.RBAC, error) {
+	mode := domain.GroupRBACMode(r.Groups.Mode)
+
+	if mode != domain.GroupRBACModeGroup && mode != domain.GroupRBACModeMembers {
+		return domain.RBAC{}, fmt.Errorf(
+			"invalid group rbac mode %q, expected %q or %q",
+			r.Groups.Mode,
+			domain.GroupRBACModeGroup,
+			domain.GroupRBACModeMembers,
+		)
 	}
+
+	// 🔹 Member RoleBindings are only pruned once expired, they would otherwise be kept forever
+	if r.Groups.Enabled && mode == domain.GroupRBACModeMembers && r.Groups.MemberExpiry <= 0 {
+		return domain.RBAC{}, fmt.Errorf("group rbac memberExpiry must be positive in %q mode",
+			domain.GroupRBACModeMembers)
+	}
+
+	roles := make(map[string]string, len(r.Groups.Roles))
+	for _, role := range r.Groups.Roles {
+		roles[role.Group] = role.ClusterRole
+	}
+
+	return domain.RBAC{
+		Enabled:         r.Enabled,
+		RoleBindingName: r.RoleBindingName,
+		ClusterRole:     r.ClusterRole,
+		UsernamePrefix:  r.UsernamePrefix,
+		Groups: domain.GroupRBAC{
+			Enabled:         r.Groups.Enabled,
+			Mode:            mode,
+			RoleBindingName: r.Groups.RoleBindingName,
+			ClusterRole:     r.Groups.ClusterRole,
+			GroupPrefix:     r.Groups.GroupPrefix,
+			Roles:           roles,
+			MemberExpiry:    r.Groups.MemberExpiry,
+		},
+	}, nil
+}
+
+func convertBootstrapNetworkPoliciesToDomain(
+	n bootstrap.NetworkPolicies,
+) (domain.NetworkPolicies, error) {
+	convert := func(policies []bootstrap.NetworkPolicy) ([]domain.NetworkPolicy, error) {
+		result := make([]domain.NetworkPolicy, 0, len(policies))
+		for _, policy := range policies {
+			if _, err := kubernetes.ParseNetworkPolicySpec(policy.Spec); err != nil {
+				return nil, fmt.Errorf("invalid network policy %q: %w", policy.Name, err)
+			}
+			result = append(result, domain.NetworkPolicy(policy))
+		}
+		return result, nil
+	}
+
+	user, err := convert(n.User)
+	if err != nil {
+		return domain.NetworkPolicies{}, err
+	}
+
+	group, err := convert(n.Group)
+	if err != nil {
+		return domain.NetworkPolicies{}, err
+	}
+
+	return domain.NetworkPolicies{Enabled: n.Enabled, User: user, Group: group}, nil
+}
+
+// loadManifests parses the manifest templates matched by the configured paths. Each path is a file
+// or a glob pattern, and must match at least one file.
+func loadManifests(m bootstrap.Manifests, region string) (domain.Manifests, error) {
+	if !m.Enabled {
+		return domain.Manifests{}, nil
+	}
+
+	var templates []domain.Manifest
+	for _, path := range m.Paths {
+		files, err := filepath.Glob(path)
+		if err != nil {
+			return domain.Manifests{}, fmt.Errorf("invalid manifest path %q: %w", path, err)
+		}
+		if len(files) == 0 {
+			return domain.Manifests{}, fmt.Errorf("no manifest found at %q", path)
+		}
+
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return domain.Manifests{}, fmt.Errorf("failed to read manifest %q: %w", file, err)
+			}
+
+			tmpl, err := template.New(filepath.Base(file)).
+				Option("missingkey=error").
+				Parse(string(content))
+			if err != nil {
+				return domain.Manifests{}, fmt.Errorf("invalid manifest %q: %w", file, err)
+			}
+
+			templates = append(templates, domain.Manifest{Name: file, Template: tmpl})
+		}
+	}
+
+	return domain.Manifests{Enabled: true, Region: region, Templates: templates}, nil
 }
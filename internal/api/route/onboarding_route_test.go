@@ -1,7 +1,10 @@
 package route
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
@@ -20,6 +23,11 @@ func TestConvertBootstrapQuotaToDomain(t *testing.T) {
 		LimitsEphemeralStorage:   "10Gi",
 		RequestsGPU:              "1",
 		LimitsGPU:                "2",
+		LimitRange: bootstrap.LimitRange{
+			DefaultCPU:    "500m",
+			DefaultMemory: "1Gi",
+			MaxCPU:        "2",
+		},
 	}
 
 	expectedDomainQuota := domain.Quota{
@@ -33,9 +41,342 @@ func TestConvertBootstrapQuotaToDomain(t *testing.T) {
 		EphemeralStorageLimit:   bootstrapQuota.LimitsEphemeralStorage,
 		GPURequest:              bootstrapQuota.RequestsGPU,
 		GPULimit:                bootstrapQuota.LimitsGPU,
+		LimitRange: domain.LimitRange{
+			DefaultCPU:    "500m",
+			DefaultMemory: "1Gi",
+			MaxCPU:        "2",
+		},
 	}
 
 	result := convertBootstrapQuotaToDomain(bootstrapQuota)
 
 	assert.Equal(t, expectedDomainQuota, result, "Quota conversion should correctly map all fields")
 }
+
+func TestConvertBootstrapQuotaToDomain_Resources(t *testing.T) {
+	result := convertBootstrapQuotaToDomain(bootstrap.Quota{
+		Resources: []bootstrap.QuotaResource{
+			{Name: "amd.com/gpu", Quantity: "1"},
+			{Name: "count/services.loadbalancers", Quantity: "0"},
+		},
+	})
+
+	assert.Equal(t, map[string]string{
+		"amd.com/gpu":                  "1",
+		"count/services.loadbalancers": "0",
+	}, result.Resources)
+}
+
+func TestConvertBootstrapQuotaToDomain_Scoped(t *testing.T) {
+	result := convertBootstrapQuotaToDomain(bootstrap.Quota{
+		Scoped: []bootstrap.ScopedQuota{{
+			Name: "gpu-high",
+			ScopeSelector: []bootstrap.ScopeSelectorRequirement{
+				{ScopeName: "PriorityClass", Operator: "In", Values: []string{"high"}},
+			},
+			Resources: []bootstrap.QuotaResource{{Name: "requests.nvidia.com/gpu", Quantity: "1"}},
+		}},
+	})
+
+	assert.Equal(t, []domain.ScopedQuota{{
+		Name: "gpu-high",
+		ScopeSelector: []domain.ScopeSelectorRequirement{
+			{ScopeName: "PriorityClass", Operator: "In", Values: []string{"high"}},
+		},
+		Hard: map[string]string{"requests.nvidia.com/gpu": "1"},
+	}}, result.Scoped)
+}
+
+func TestValidateQuotas(t *testing.T) {
+	quotas := domain.Quotas{
+		Enabled: true,
+		Default: domain.Quota{CPURequest: "4"},
+		Roles: map[string]domain.Quota{
+			"admin": {Resources: map[string]string{"amd.com/gpu": "many"}},
+		},
+	}
+
+	err := validateQuotas(quotas)
+	assert.ErrorContains(t, err, "invalid roles.admin quota")
+
+	quotas.Enabled = false
+	assert.NoError(t, validateQuotas(quotas))
+}
+
+func TestConvertBootstrapGroupQuotasToDomain(t *testing.T) {
+	result, err := convertBootstrapGroupQuotasToDomain([]bootstrap.GroupQuota{
+		{Group: "research-lab", Quota: bootstrap.Quota{RequestsCPU: "40"}},
+		{Pattern: "students-*"},
+		{Regex: "^funded-[0-9]+$"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, "research-lab", result[0].Name)
+	assert.Equal(t, "40", result[0].Quota.CPURequest)
+	assert.Equal(t, "students-*", result[1].Pattern)
+	assert.True(t, result[2].Regex.MatchString("funded-42"))
+}
+
+func TestConvertBootstrapGroupQuotasToDomain_Invalid(t *testing.T) {
+	_, err := convertBootstrapGroupQuotasToDomain([]bootstrap.GroupQuota{
+		{Group: "research-lab", Pattern: "research-*"},
+	})
+	assert.ErrorContains(t, err, "exactly one of group, pattern or regex")
+
+	_, err = convertBootstrapGroupQuotasToDomain([]bootstrap.GroupQuota{{Pattern: "[research"}})
+	assert.ErrorContains(t, err, "invalid group quota pattern")
+
+	_, err = convertBootstrapGroupQuotasToDomain([]bootstrap.GroupQuota{{Regex: "(funded"}})
+	assert.ErrorContains(t, err, "invalid group quota regex")
+}
+
+func TestConvertBootstrapRoleMergeStrategyToDomain(t *testing.T) {
+	strategy, err := convertBootstrapRoleMergeStrategyToDomain(bootstrap.Quotas{})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleMergeStrategyFirst, strategy)
+
+	strategy, err = convertBootstrapRoleMergeStrategyToDomain(bootstrap.Quotas{
+		RoleMergeStrategy: "priority",
+		Roles:             map[string]bootstrap.Quota{"admin": {}},
+		RolePriority:      []string{"admin"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleMergeStrategyPriority, strategy)
+}
+
+func TestConvertBootstrapRoleMergeStrategyToDomain_Invalid(t *testing.T) {
+	_, err := convertBootstrapRoleMergeStrategyToDomain(bootstrap.Quotas{RoleMergeStrategy: "min"})
+	assert.Error(t, err)
+
+	_, err = convertBootstrapRoleMergeStrategyToDomain(bootstrap.Quotas{
+		RoleMergeStrategy: "priority",
+		RolePriority:      []string{"admin"},
+	})
+	assert.ErrorContains(t, err, "role \"admin\" of rolePriority has no quota")
+}
+
+func TestConvertBootstrapQuotaRemovalPolicyToDomain(t *testing.T) {
+	policy, err := convertBootstrapQuotaRemovalPolicyToDomain("")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.QuotaRemovalPolicyKeep, policy)
+
+	policy, err = convertBootstrapQuotaRemovalPolicyToDomain("delete")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.QuotaRemovalPolicyDelete, policy)
+
+	_, err = convertBootstrapQuotaRemovalPolicyToDomain("purge")
+	assert.Error(t, err)
+}
+
+func TestConvertBootstrapOffboardingToDomain(t *testing.T) {
+	result, err := convertBootstrapOffboardingToDomain(bootstrap.Offboarding{
+		Enabled:     true,
+		Mode:        "archive",
+		GracePeriod: 48 * time.Hour,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Offboarding{
+		Enabled:     true,
+		Mode:        domain.OffboardingModeArchive,
+		GracePeriod: 48 * time.Hour,
+	}, result)
+}
+
+func TestConvertBootstrapOffboardingToDomain_InvalidMode(t *testing.T) {
+	_, err := convertBootstrapOffboardingToDomain(bootstrap.Offboarding{Mode: "shred"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid offboarding mode")
+}
+
+func TestConvertBootstrapRBACToDomain(t *testing.T) {
+	result, err := convertBootstrapRBACToDomain(bootstrap.RBAC{
+		Enabled:         true,
+		RoleBindingName: "onyxia-user",
+		ClusterRole:     "admin",
+		UsernamePrefix:  "oidc:",
+		Groups: bootstrap.GroupRBAC{
+			Enabled:         true,
+			Mode:            "members",
+			RoleBindingName: "onyxia-member",
+			ClusterRole:     "edit",
+			GroupPrefix:     "oidc:",
+			Roles:           []bootstrap.GroupRole{{Group: "funded", ClusterRole: "admin"}},
+			MemberExpiry:    720 * time.Hour,
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.GroupRBACModeMembers, result.Groups.Mode)
+	assert.Equal(t, 720*time.Hour, result.Groups.MemberExpiry)
+	assert.Equal(t, map[string]string{"funded": "admin"}, result.Groups.Roles)
+	assert.Equal(t, "oidc:", result.UsernamePrefix)
+}
+
+func TestConvertBootstrapRBACToDomain_InvalidMode(t *testing.T) {
+	_, err := convertBootstrapRBACToDomain(bootstrap.RBAC{
+		Groups: bootstrap.GroupRBAC{Mode: "everyone"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid group rbac mode")
+}
+
+func TestConvertBootstrapRBACToDomain_MembersWithoutExpiry(t *testing.T) {
+	_, err := convertBootstrapRBACToDomain(bootstrap.RBAC{
+		Groups: bootstrap.GroupRBAC{Enabled: true, Mode: "members"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "memberExpiry must be positive")
+}
+
+func TestConvertBootstrapNetworkPoliciesToDomain(t *testing.T) {
+	result, err := convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Enabled: true,
+		User:    []bootstrap.NetworkPolicy{{Name: "deny-all-ingress", Spec: "podSelector: {}"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NetworkPolicies{
+		Enabled: true,
+		User:    []domain.NetworkPolicy{{Name: "deny-all-ingress", Spec: "podSelector: {}"}},
+		Group:   []domain.NetworkPolicy{},
+	}, result)
+}
+
+func TestConvertBootstrapNetworkPoliciesToDomain_InvalidSpec(t *testing.T) {
+	_, err := convertBootstrapNetworkPoliciesToDomain(bootstrap.NetworkPolicies{
+		Group: []bootstrap.NetworkPolicy{{Name: "broken", Spec: "podSelector: []"}},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid network policy \"broken\"")
+}
+
+func TestLoadManifests(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a-service-account.yaml", "b-ca-bundle.yaml"} {
+		err := os.WriteFile(
+			filepath.Join(dir, name),
+			[]byte("kind: ConfigMap\nmetadata:\n  name: {{ .Namespace }}\n"),
+			0o600,
+		)
+		assert.NoError(t, err)
+	}
+
+	manifests, err := loadManifests(
+		bootstrap.Manifests{Enabled: true, Paths: []string{filepath.Join(dir, "*.yaml")}},
+		"eu-west",
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, manifests.Enabled)
+	assert.Equal(t, "eu-west", manifests.Region)
+	assert.Len(t, manifests.Templates, 2)
+	assert.Equal(t, filepath.Join(dir, "a-service-account.yaml"), manifests.Templates[0].Name)
+}
+
+func TestLoadManifests_Disabled(t *testing.T) {
+	manifests, err := loadManifests(
+		bootstrap.Manifests{Enabled: false, Paths: []string{"/does/not/exist.yaml"}},
+		"",
+	)
+
+	assert.NoError(t, err)
+	assert.False(t, manifests.Enabled)
+}
+
+func TestLoadManifests_NoMatch(t *testing.T) {
+	_, err := loadManifests(
+		bootstrap.Manifests{Enabled: true, Paths: []string{filepath.Join(t.TempDir(), "*.yaml")}},
+		"",
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no manifest found")
+}
+
+func TestLoadManifests_InvalidTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("name: {{ .Namespace "), 0o600))
+
+	_, err := loadManifests(bootstrap.Manifests{Enabled: true, Paths: []string{path}}, "")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid manifest")
+}
+
+func TestParseNamespaceTemplate(t *testing.T) {
+	tmpl, err := parseNamespaceTemplate("namespaceTemplate", "")
+	assert.NoError(t, err)
+	assert.Nil(t, tmpl, "Expected no template so that the prefix is used")
+
+	tmpl, err = parseNamespaceTemplate(
+		"namespaceTemplate",
+		"{{ .Claims.department }}-{{ .Username }}",
+	)
+	assert.NoError(t, err)
+	assert.NotNil(t, tmpl)
+
+	_, err = parseNamespaceTemplate("namespaceTemplate", "{{ .Usernam }}")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid namespaceTemplate")
+}
+
+func TestConvertBootstrapDynamicLabelsToDomain(t *testing.T) {
+	labels, err := convertBootstrapDynamicLabelsToDomain([]bootstrap.DynamicLabel{
+		{Name: "onyxia.sh/department", Value: "{{ .Claims.department }}"},
+	}, map[string]string{"created-by": "onyxia"})
+
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+	assert.Equal(t, "onyxia.sh/department", labels[0].Name)
+}
+
+func TestConvertBootstrapDynamicLabelsToDomain_Invalid(t *testing.T) {
+	tests := map[string][]bootstrap.DynamicLabel{
+		"name":     {{Name: "not a label", Value: "x"}},
+		"static":   {{Name: "created-by", Value: "x"}},
+		"template": {{Name: "team", Value: "{{ .Grp }}"}},
+		"twice":    {{Name: "team", Value: "a"}, {Name: "team", Value: "b"}},
+	}
+
+	for name, labels := range tests {
+		_, err := convertBootstrapDynamicLabelsToDomain(
+			labels,
+			map[string]string{"created-by": "onyxia"},
+		)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestValidateReservedNamespaces(t *testing.T) {
+	assert.NoError(t, validateReservedNamespaces([]string{"default", "kube-*"}))
+	assert.ErrorContains(
+		t,
+		validateReservedNamespaces([]string{"kube-["}),
+		"invalid reserved namespace pattern \"kube-[\"",
+	)
+}
+
+func TestValidateSkipIfOnboardedWithin(t *testing.T) {
+	var annotation bootstrap.Annotation
+	assert.NoError(t, validateSkipIfOnboardedWithin(0, annotation))
+	assert.ErrorContains(
+		t,
+		validateSkipIfOnboardedWithin(time.Minute, annotation),
+		"requires annotations.dynamic.last-login-timestamp",
+	)
+
+	annotation.Enabled = true
+	annotation.Dynamic.LastLoginTimestamp = true
+	assert.NoError(t, validateSkipIfOnboardedWithin(time.Minute, annotation))
+	assert.ErrorContains(
+		t,
+		validateSkipIfOnboardedWithin(-time.Minute, annotation),
+		"must not be negative",
+	)
+}
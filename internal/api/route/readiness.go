@@ -0,0 +1,39 @@
+package route
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+)
+
+// startNamespaceCache fills the namespace cache in the background when it is enabled. Until it
+// is synced, e.g. without the permissions to watch, namespaces and quotas are read from the API
+// server.
+func startNamespaceCache(ctx context.Context, app *bootstrap.Application) {
+	if app.NamespaceCache == nil {
+		return
+	}
+
+	app.NamespaceCache.Start(ctx)
+
+	go func() {
+		if app.NamespaceCache.WaitForSync(ctx) {
+			slog.Info("✅ Namespace cache synced")
+		}
+	}()
+}
+
+// Readiness answers 200 once the service is up. An unsynced namespace cache does not keep it from
+// being ready, as objects are then read from the API server, but it is reported.
+func Readiness(app *bootstrap.Application) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		if app.NamespaceCache != nil && !app.NamespaceCache.HasSynced() {
+			_, _ = w.Write([]byte("ok, namespace cache not synced"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}
+}
@@ -0,0 +1,41 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func readiness(app *bootstrap.Application) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	Readiness(app)(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	return recorder
+}
+
+func TestReadiness(t *testing.T) {
+	// ✅ Without cache, the service is ready right away
+	assert.Equal(t, http.StatusOK, readiness(&bootstrap.Application{}).Code)
+
+	// ✅ An unsynced cache is reported, reads fall back to the API server
+	app := &bootstrap.Application{
+		NamespaceCache: kubernetes.NewNamespaceCache(fake.NewClientset()),
+	}
+	recorder := readiness(app)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "namespace cache not synced")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	app.NamespaceCache.Start(ctx)
+	assert.True(t, app.NamespaceCache.WaitForSync(ctx))
+
+	recorder = readiness(app)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ok", recorder.Body.String())
+}
@@ -0,0 +1,135 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/kubernetes"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/usecase"
+)
+
+// StartReaper runs the idle namespace reaper in the background when it is enabled.
+// Only the replica holding the reaper Lease reaps namespaces.
+func StartReaper(ctx context.Context, app *bootstrap.Application) error {
+	reaper, err := convertBootstrapReaperToDomain(
+		app.Env.Onboarding.Reaper,
+		app.Env.Onboarding.Offboarding,
+		app.Env.Onboarding.Annotation,
+	)
+	if err != nil {
+		return err
+	}
+
+	if !reaper.Enabled {
+		return nil
+	}
+	reaper.NamespaceLabels = app.Env.Onboarding.NamespaceLabels
+	reaper.ReservedNames = app.Env.Onboarding.ReservedNamespaces
+
+	reaperUsecase := usecase.NewIdleReaper(
+		kubernetes.NewKubernetesNamespaceService(app.K8sClient.Clientset),
+		kubernetes.NewKubernetesIdleNamespaceService(app.K8sClient.Clientset),
+		reaper,
+	)
+
+	go func() {
+		err := kubernetes.RunWithLeaderElection(
+			ctx,
+			app.K8sClient.Clientset,
+			reaper.LeaseNamespace,
+			reaper.LeaseName,
+			reaperUsecase.Run,
+		)
+		if err != nil {
+			slog.Error("❌ Idle namespace reaper stopped", slog.Any("error", err))
+		}
+	}()
+
+	slog.Info("✅ Idle namespace reaper started",
+		slog.Duration("interval", reaper.Interval),
+		slog.Bool("reportOnly", reaper.ReportOnly),
+	)
+
+	return nil
+}
+
+func convertBootstrapReaperToDomain(
+	r bootstrap.Reaper,
+	o bootstrap.Offboarding,
+	annotation bootstrap.Annotation,
+) (domain.Reaper, error) {
+	reaper := domain.Reaper{
+		Enabled:        r.Enabled,
+		ReportOnly:     r.ReportOnly,
+		Interval:       r.Interval,
+		WarnAfter:      r.WarnAfter,
+		ScaleDownAfter: r.ScaleDownAfter,
+		ArchiveAfter:   r.ArchiveAfter,
+		DeleteAfter:    r.DeleteAfter,
+		GracePeriod:    o.GracePeriod,
+		LeaseName:      r.LeaderElection.LeaseName,
+		LeaseNamespace: r.LeaderElection.LeaseNamespace,
+	}
+
+	if !reaper.Enabled {
+		return reaper, nil
+	}
+
+	if reaper.Interval <= 0 {
+		return domain.Reaper{}, fmt.Errorf("invalid reaper interval %s", reaper.Interval)
+	}
+
+	// 🔹 Idle durations are computed from the last login annotation
+	if !annotation.Enabled || !annotation.Dynamic.LastLoginTimestamp {
+		return domain.Reaper{}, fmt.Errorf(
+			"reaper requires annotations.dynamic.last-login-timestamp",
+		)
+	}
+
+	if reaper.LeaseName == "" {
+		return domain.Reaper{}, fmt.Errorf("reaper leader election requires a lease name")
+	}
+
+	// 🔹 Actions escalate: each enabled threshold must be later than the previous ones
+	var previous time.Duration
+	for _, threshold := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"warnAfter", reaper.WarnAfter},
+		{"scaleDownAfter", reaper.ScaleDownAfter},
+		{"archiveAfter", reaper.ArchiveAfter},
+		{"deleteAfter", reaper.DeleteAfter},
+	} {
+		if threshold.value < 0 {
+			return domain.Reaper{}, fmt.Errorf("invalid reaper %s %s", threshold.name, threshold.value)
+		}
+		if threshold.value == 0 {
+			continue
+		}
+		if threshold.value <= previous {
+			return domain.Reaper{}, fmt.Errorf(
+				"reaper %s (%s) must be longer than the previous thresholds",
+				threshold.name,
+				threshold.value,
+			)
+		}
+		previous = threshold.value
+	}
+
+	if reaper.DeleteAfter > 0 && reaper.ArchiveAfter == 0 {
+		return domain.Reaper{}, fmt.Errorf(
+			"reaper deleteAfter requires archiveAfter: namespaces are archived before deletion",
+		)
+	}
+
+	if previous == 0 {
+		return domain.Reaper{}, fmt.Errorf("reaper is enabled but no threshold is set")
+	}
+
+	return reaper, nil
+}
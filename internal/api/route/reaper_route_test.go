@@ -0,0 +1,99 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/stretchr/testify/assert"
+)
+
+func validReaper() bootstrap.Reaper {
+	return bootstrap.Reaper{
+		Enabled:        true,
+		Interval:       time.Hour,
+		WarnAfter:      720 * time.Hour,
+		ArchiveAfter:   2160 * time.Hour,
+		LeaderElection: bootstrap.LeaderElection{LeaseName: "onyxia-onboarding-reaper"},
+	}
+}
+
+func lastLoginAnnotation() bootstrap.Annotation {
+	annotation := bootstrap.Annotation{Enabled: true}
+	annotation.Dynamic.LastLoginTimestamp = true
+	return annotation
+}
+
+func TestConvertBootstrapReaperToDomain(t *testing.T) {
+	result, err := convertBootstrapReaperToDomain(
+		validReaper(),
+		bootstrap.Offboarding{GracePeriod: 48 * time.Hour},
+		lastLoginAnnotation(),
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Enabled)
+	assert.Equal(t, 720*time.Hour, result.WarnAfter)
+	assert.Equal(t, time.Duration(0), result.ScaleDownAfter)
+	assert.Equal(t, 48*time.Hour, result.GracePeriod)
+	assert.Equal(t, "onyxia-onboarding-reaper", result.LeaseName)
+}
+
+func TestConvertBootstrapReaperToDomain_UnorderedThresholds(t *testing.T) {
+	reaper := validReaper()
+	reaper.ScaleDownAfter = 24 * time.Hour
+
+	_, err := convertBootstrapReaperToDomain(
+		reaper,
+		bootstrap.Offboarding{},
+		lastLoginAnnotation(),
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "scaleDownAfter")
+}
+
+func TestConvertBootstrapReaperToDomain_DeleteWithoutArchive(t *testing.T) {
+	reaper := validReaper()
+	reaper.ArchiveAfter = 0
+	reaper.DeleteAfter = 4320 * time.Hour
+
+	_, err := convertBootstrapReaperToDomain(
+		reaper,
+		bootstrap.Offboarding{},
+		lastLoginAnnotation(),
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "deleteAfter requires archiveAfter")
+}
+
+func TestConvertBootstrapReaperToDomain_LastLoginDisabled(t *testing.T) {
+	// ❌ Idle durations cannot be computed without the last login annotation
+	for name, annotation := range map[string]bootstrap.Annotation{
+		"annotations disabled": {},
+		"last login disabled":  {Enabled: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := convertBootstrapReaperToDomain(
+				validReaper(),
+				bootstrap.Offboarding{},
+				annotation,
+			)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "last-login-timestamp")
+		})
+	}
+}
+
+func TestConvertBootstrapReaperToDomain_Disabled(t *testing.T) {
+	// ✅ Invalid values are not checked when the reaper is disabled
+	_, err := convertBootstrapReaperToDomain(
+		bootstrap.Reaper{},
+		bootstrap.Offboarding{},
+		bootstrap.Annotation{},
+	)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,86 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/kubernetes"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/usecase"
+)
+
+// ReconcileQuotas reconciles the quotas of every onboarded namespace once, e.g. from the
+// `reconcile` command after a quota profile was changed.
+func ReconcileQuotas(
+	ctx context.Context,
+	app *bootstrap.Application,
+) (domain.QuotaReconcileSummary, error) {
+	onboardingUsecase, err := newOnboardingUsecase(app, app.Env.Onboarding)
+	if err != nil {
+		return domain.QuotaReconcileSummary{}, err
+	}
+
+	return onboardingUsecase.ReconcileQuotas(ctx)
+}
+
+// startQuotaReconciler reconciles quotas in the background when it is enabled, with the
+// onboarding usecase serving requests so that reloaded configurations are reconciled too.
+// Only the replica holding the reconcile Lease reconciles namespaces.
+func startQuotaReconciler(
+	ctx context.Context,
+	app *bootstrap.Application,
+	onboardingUsecase domain.OnboardingUsecase,
+) error {
+	reconcile, err := convertBootstrapReconcileToDomain(app.Env.Onboarding.Reconcile)
+	if err != nil {
+		return err
+	}
+
+	if !reconcile.Enabled {
+		return nil
+	}
+
+	reconciler := usecase.NewQuotaReconciler(onboardingUsecase, reconcile.Interval)
+
+	go func() {
+		err := kubernetes.RunWithLeaderElection(
+			ctx,
+			app.K8sClient.Clientset,
+			reconcile.LeaseNamespace,
+			reconcile.LeaseName,
+			reconciler.Run,
+		)
+		if err != nil {
+			slog.Error("❌ Quota reconciler stopped", slog.Any("error", err))
+		}
+	}()
+
+	slog.Info("✅ Quota reconciler started", slog.Duration("interval", reconcile.Interval))
+
+	return nil
+}
+
+func convertBootstrapReconcileToDomain(r bootstrap.Reconcile) (domain.Reconcile, error) {
+	reconcile := domain.Reconcile{
+		Enabled:        r.Enabled,
+		Interval:       r.Interval,
+		LeaseName:      r.LeaderElection.LeaseName,
+		LeaseNamespace: r.LeaderElection.LeaseNamespace,
+	}
+
+	if !reconcile.Enabled {
+		return reconcile, nil
+	}
+
+	if reconcile.Interval <= 0 {
+		return domain.Reconcile{}, fmt.Errorf("invalid reconcile interval %s", reconcile.Interval)
+	}
+
+	if reconcile.LeaseName == "" {
+		return domain.Reconcile{}, fmt.Errorf("reconcile leader election requires a lease name")
+	}
+
+	return reconcile, nil
+}
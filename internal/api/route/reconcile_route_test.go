@@ -0,0 +1,37 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/bootstrap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertBootstrapReconcileToDomain(t *testing.T) {
+	result, err := convertBootstrapReconcileToDomain(bootstrap.Reconcile{
+		Enabled:        true,
+		Interval:       24 * time.Hour,
+		LeaderElection: bootstrap.LeaderElection{LeaseName: "onyxia-onboarding-reconcile"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Enabled)
+	assert.Equal(t, 24*time.Hour, result.Interval)
+	assert.Equal(t, "onyxia-onboarding-reconcile", result.LeaseName)
+}
+
+func TestConvertBootstrapReconcileToDomain_Invalid(t *testing.T) {
+	_, err := convertBootstrapReconcileToDomain(bootstrap.Reconcile{Enabled: true})
+	assert.Error(t, err)
+
+	_, err = convertBootstrapReconcileToDomain(bootstrap.Reconcile{
+		Enabled:  true,
+		Interval: time.Hour,
+	})
+	assert.Error(t, err)
+
+	// ✅ Invalid values are not checked when reconciliation is disabled
+	_, err = convertBootstrapReconcileToDomain(bootstrap.Reconcile{})
+	assert.NoError(t, err)
+}
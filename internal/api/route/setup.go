@@ -23,9 +23,26 @@ func Setup(app *bootstrap.Application) (http.Handler, error) {
 		return nil, fmt.Errorf("failed to initialize OIDC middleware: %w", err)
 	}
 
-	onboardingController := SetupOnboardingController(app)
+	startNamespaceCache(context.Background(), app)
 
-	handler := &MyHandler{onboardImpl: onboardingController.Onboard}
+	onboardingController, err := SetupOnboardingController(app)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up onboarding controller: %w", err)
+	}
+
+	if err := startQuotaReconciler(
+		context.Background(),
+		app,
+		onboardingController.OnboardingUsecase,
+	); err != nil {
+		return nil, fmt.Errorf("failed to start quota reconciler: %w", err)
+	}
+
+	handler := &MyHandler{
+		onboardImpl:  onboardingController.Onboard,
+		offboardImpl: onboardingController.Offboard,
+		statusImpl:   onboardingController.GetOnboardingStatus,
+	}
 
 	srv, err := oas.NewServer(
 		handler,
@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"fmt"
 	"log/slog"
+	"net/http"
 
 	usercontext "github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/context"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/kubernetes"
@@ -12,6 +13,8 @@ import (
 type Application struct {
 	Env               *Env
 	K8sClient         *kubernetes.KubernetesClient
+	NamespaceCache    *kubernetes.NamespaceCache // nil when the cache is disabled
+	MetricsHandler    http.Handler               // nil when metrics are disabled
 	UserContextReader interfaces.UserContextReader
 	UserContextWriter interfaces.UserContextWriter
 }
@@ -39,6 +42,16 @@ func NewApplication() (*Application, error) {
 		UserContextWriter: userWriter,
 	}
 
+	if env.Cache.Enabled {
+		app.NamespaceCache = kubernetes.NewNamespaceCache(k8sClient.Clientset)
+	}
+
+	if env.Metrics.Enabled {
+		if app.MetricsHandler, err = InitMetrics(); err != nil {
+			return nil, fmt.Errorf("failed to initialize metrics: %w", err)
+		}
+	}
+
 	slog.Info("Application initialized successfully")
 
 	return app, nil
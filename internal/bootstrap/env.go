@@ -6,13 +6,17 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 //go:embed env.default.yaml
 var defaultConfig []byte
 
+const configFile = "env.yaml"
+
 type Server struct {
 	Port        int    `mapstructure:"port"        json:"port"`
 	ContextPath string `mapstructure:"contextPath" json:"contextPath"`
@@ -32,27 +36,68 @@ type Security struct {
 	CORSAllowedOrigins []string `mapstructure:"corsAllowedOrigins" json:"corsAllowedOrigins"`
 }
 
+type LimitRange struct {
+	DefaultCPU           string `mapstructure:"defaultCPU"           json:"defaultCPU"`
+	DefaultMemory        string `mapstructure:"defaultMemory"        json:"defaultMemory"`
+	DefaultRequestCPU    string `mapstructure:"defaultRequestCPU"    json:"defaultRequestCPU"`
+	DefaultRequestMemory string `mapstructure:"defaultRequestMemory" json:"defaultRequestMemory"`
+	MaxCPU               string `mapstructure:"maxCPU"               json:"maxCPU"`
+	MaxMemory            string `mapstructure:"maxMemory"            json:"maxMemory"`
+}
+
+type QuotaResource struct {
+	Name     string `mapstructure:"name"     json:"name"`
+	Quantity string `mapstructure:"quantity" json:"quantity"`
+}
+
+type ScopeSelectorRequirement struct {
+	ScopeName string   `mapstructure:"scopeName" json:"scopeName"`
+	Operator  string   `mapstructure:"operator"  json:"operator"`
+	Values    []string `mapstructure:"values"    json:"values"`
+}
+
+type ScopedQuota struct {
+	Name          string                     `mapstructure:"name"          json:"name"`
+	Scopes        []string                   `mapstructure:"scopes"        json:"scopes"`
+	ScopeSelector []ScopeSelectorRequirement `mapstructure:"scopeSelector" json:"scopeSelector"`
+	Resources     []QuotaResource            `mapstructure:"resources"     json:"resources"`
+}
+
 type Quota struct {
-	RequestsMemory           string `mapstructure:"requests.memory"            json:"requests.memory"`
-	RequestsCPU              string `mapstructure:"requests.cpu"               json:"requests.cpu"`
-	LimitsMemory             string `mapstructure:"limits.memory"              json:"limits.memory"`
-	LimitsCPU                string `mapstructure:"limits.cpu"                 json:"limits.cpu"`
-	RequestsStorage          string `mapstructure:"requests.storage"           json:"requests.storage"`
-	CountPods                string `mapstructure:"count/pods"                 json:"count/pods"`
-	RequestsEphemeralStorage string `mapstructure:"requests.ephemeral-storage" json:"requests.ephemeral-storage"`
-	LimitsEphemeralStorage   string `mapstructure:"limits.ephemeral-storage"   json:"limits.ephemeral-storage"`
-	RequestsGPU              string `mapstructure:"requests.nvidia.com/gpu"    json:"requests.nvidia.com/gpu"`
-	LimitsGPU                string `mapstructure:"limits.nvidia.com/gpu"      json:"limits.nvidia.com/gpu"`
+	RequestsMemory           string          `mapstructure:"requests.memory"            json:"requests.memory"`
+	RequestsCPU              string          `mapstructure:"requests.cpu"               json:"requests.cpu"`
+	LimitsMemory             string          `mapstructure:"limits.memory"              json:"limits.memory"`
+	LimitsCPU                string          `mapstructure:"limits.cpu"                 json:"limits.cpu"`
+	RequestsStorage          string          `mapstructure:"requests.storage"           json:"requests.storage"`
+	CountPods                string          `mapstructure:"count/pods"                 json:"count/pods"`
+	RequestsEphemeralStorage string          `mapstructure:"requests.ephemeral-storage" json:"requests.ephemeral-storage"`
+	LimitsEphemeralStorage   string          `mapstructure:"limits.ephemeral-storage"   json:"limits.ephemeral-storage"`
+	RequestsGPU              string          `mapstructure:"requests.nvidia.com/gpu"    json:"requests.nvidia.com/gpu"`
+	LimitsGPU                string          `mapstructure:"limits.nvidia.com/gpu"      json:"limits.nvidia.com/gpu"`
+	Resources                []QuotaResource `mapstructure:"resources"                  json:"resources"`
+	Scoped                   []ScopedQuota   `mapstructure:"scoped"                     json:"scoped"`
+	LimitRange               LimitRange      `mapstructure:"limitRange"                 json:"limitRange"`
+}
+
+type GroupQuota struct {
+	Group   string `mapstructure:"group"   json:"group"`
+	Pattern string `mapstructure:"pattern" json:"pattern"`
+	Regex   string `mapstructure:"regex"   json:"regex"`
+	Quota   Quota  `mapstructure:"quota"   json:"quota"`
 }
 
 type Quotas struct {
-	Enabled      bool             `mapstructure:"enabled"      json:"enabled"`
-	Default      Quota            `mapstructure:"default"      json:"default"`
-	UserEnabled  bool             `mapstructure:"userEnabled"  json:"userEnabled"`
-	User         Quota            `mapstructure:"user"         json:"user"`
-	GroupEnabled bool             `mapstructure:"groupEnabled" json:"groupEnabled"`
-	Group        Quota            `mapstructure:"group"        json:"group"`
-	Roles        map[string]Quota `mapstructure:"roles"        json:"roles"`
+	Enabled           bool             `mapstructure:"enabled"           json:"enabled"`
+	Default           Quota            `mapstructure:"default"           json:"default"`
+	UserEnabled       bool             `mapstructure:"userEnabled"       json:"userEnabled"`
+	User              Quota            `mapstructure:"user"              json:"user"`
+	GroupEnabled      bool             `mapstructure:"groupEnabled"      json:"groupEnabled"`
+	Group             Quota            `mapstructure:"group"             json:"group"`
+	Groups            []GroupQuota     `mapstructure:"groups"            json:"groups"`
+	Roles             map[string]Quota `mapstructure:"roles"             json:"roles"`
+	RoleMergeStrategy string           `mapstructure:"roleMergeStrategy" json:"roleMergeStrategy"`
+	RolePriority      []string         `mapstructure:"rolePriority"      json:"rolePriority"`
+	RemovalPolicy     string           `mapstructure:"removalPolicy"     json:"removalPolicy"`
 }
 
 type Annotation struct {
@@ -63,12 +108,107 @@ type Annotation struct {
 		UserAttributes     []string `mapstructure:"userAttributes" json:"userAttributes"`
 	} `mapstructure:"dynamic" json:"dynamic"`
 }
+type Offboarding struct {
+	Enabled     bool          `mapstructure:"enabled"     json:"enabled"`
+	Mode        string        `mapstructure:"mode"        json:"mode"`
+	GracePeriod time.Duration `mapstructure:"gracePeriod" json:"gracePeriod"`
+}
+
+type GroupRole struct {
+	Group       string `mapstructure:"group"       json:"group"`
+	ClusterRole string `mapstructure:"clusterRole" json:"clusterRole"`
+}
+
+type GroupRBAC struct {
+	Enabled         bool          `mapstructure:"enabled"         json:"enabled"`
+	Mode            string        `mapstructure:"mode"            json:"mode"`
+	RoleBindingName string        `mapstructure:"roleBindingName" json:"roleBindingName"`
+	ClusterRole     string        `mapstructure:"clusterRole"     json:"clusterRole"`
+	GroupPrefix     string        `mapstructure:"groupPrefix"     json:"groupPrefix"`
+	Roles           []GroupRole   `mapstructure:"roles"           json:"roles"`
+	MemberExpiry    time.Duration `mapstructure:"memberExpiry"    json:"memberExpiry"`
+}
+
+type RBAC struct {
+	Enabled         bool      `mapstructure:"enabled"         json:"enabled"`
+	RoleBindingName string    `mapstructure:"roleBindingName" json:"roleBindingName"`
+	ClusterRole     string    `mapstructure:"clusterRole"     json:"clusterRole"`
+	UsernamePrefix  string    `mapstructure:"usernamePrefix"  json:"usernamePrefix"`
+	Groups          GroupRBAC `mapstructure:"groups"          json:"groups"`
+}
+
+type NetworkPolicy struct {
+	Name string `mapstructure:"name" json:"name"`
+	Spec string `mapstructure:"spec" json:"spec"`
+}
+
+type NetworkPolicies struct {
+	Enabled bool            `mapstructure:"enabled" json:"enabled"`
+	User    []NetworkPolicy `mapstructure:"user"    json:"user"`
+	Group   []NetworkPolicy `mapstructure:"group"   json:"group"`
+}
+
+type Manifests struct {
+	Enabled bool     `mapstructure:"enabled" json:"enabled"`
+	Paths   []string `mapstructure:"paths"   json:"paths"`
+}
+
+type LeaderElection struct {
+	LeaseName      string `mapstructure:"leaseName"      json:"leaseName"`
+	LeaseNamespace string `mapstructure:"leaseNamespace" json:"leaseNamespace"`
+}
+
+type Reaper struct {
+	Enabled        bool           `mapstructure:"enabled"        json:"enabled"`
+	ReportOnly     bool           `mapstructure:"reportOnly"     json:"reportOnly"`
+	Interval       time.Duration  `mapstructure:"interval"       json:"interval"`
+	WarnAfter      time.Duration  `mapstructure:"warnAfter"      json:"warnAfter"`
+	ScaleDownAfter time.Duration  `mapstructure:"scaleDownAfter" json:"scaleDownAfter"`
+	ArchiveAfter   time.Duration  `mapstructure:"archiveAfter"   json:"archiveAfter"`
+	DeleteAfter    time.Duration  `mapstructure:"deleteAfter"    json:"deleteAfter"`
+	LeaderElection LeaderElection `mapstructure:"leaderElection" json:"leaderElection"`
+}
+
+type Reconcile struct {
+	Enabled        bool           `mapstructure:"enabled"        json:"enabled"`
+	Interval       time.Duration  `mapstructure:"interval"       json:"interval"`
+	LeaderElection LeaderElection `mapstructure:"leaderElection" json:"leaderElection"`
+}
+
+type DynamicLabel struct {
+	Name  string `mapstructure:"name"  json:"name"`
+	Value string `mapstructure:"value" json:"value"`
+}
+
 type Onboarding struct {
-	NamespacePrefix      string            `mapstructure:"namespacePrefix"      json:"namespacePrefix"`
-	NamespaceLabels      map[string]string `mapstructure:"namespaceLabels"      json:"labels"`
-	GroupNamespacePrefix string            `mapstructure:"groupNamespacePrefix" json:"groupNamespacePrefix"`
-	Annotation           Annotation        `mapstructure:"annotations"          json:"annotations"`
-	Quotas               Quotas            `mapstructure:"quotas"               json:"quotas"`
+	NamespacePrefix        string            `mapstructure:"namespacePrefix"        json:"namespacePrefix"`
+	NamespaceLabels        map[string]string `mapstructure:"namespaceLabels"        json:"labels"`
+	NamespaceDynamicLabels []DynamicLabel    `mapstructure:"namespaceDynamicLabels" json:"namespaceDynamicLabels"`
+	GroupNamespacePrefix   string            `mapstructure:"groupNamespacePrefix"   json:"groupNamespacePrefix"`
+	NamespaceTemplate      string            `mapstructure:"namespaceTemplate"      json:"namespaceTemplate"`
+	GroupNamespaceTemplate string            `mapstructure:"groupNamespaceTemplate" json:"groupNamespaceTemplate"`
+	ReservedNamespaces     []string          `mapstructure:"reservedNamespaces"     json:"reservedNamespaces"`
+	Annotation             Annotation        `mapstructure:"annotations"            json:"annotations"`
+	Quotas                 Quotas            `mapstructure:"quotas"                 json:"quotas"`
+	Offboarding            Offboarding       `mapstructure:"offboarding"            json:"offboarding"`
+	RBAC                   RBAC              `mapstructure:"rbac"                   json:"rbac"`
+	NetworkPolicies        NetworkPolicies   `mapstructure:"networkPolicies"        json:"networkPolicies"`
+	Region                 string            `mapstructure:"region"                 json:"region"`
+	Manifests              Manifests         `mapstructure:"manifests"              json:"manifests"`
+	DryRun                 bool              `mapstructure:"dryRun"                 json:"dryRun"`
+	SkipIfOnboardedWithin  time.Duration     `mapstructure:"skipIfOnboardedWithin"  json:"skipIfOnboardedWithin"`
+	Reaper                 Reaper            `mapstructure:"reaper"                 json:"reaper"`
+	Reconcile              Reconcile         `mapstructure:"reconcile"              json:"reconcile"`
+	LegacyFieldManagers    []string          `mapstructure:"legacyFieldManagers"    json:"legacyFieldManagers"`
+}
+
+type Cache struct {
+	Enabled bool `mapstructure:"enabled" json:"enabled"`
+}
+
+type Metrics struct {
+	Enabled bool   `mapstructure:"enabled" json:"enabled"`
+	Path    string `mapstructure:"path"    json:"path"`
 }
 
 type Env struct {
@@ -77,35 +217,60 @@ type Env struct {
 	OIDC               OIDC       `mapstructure:"oidc"               json:"oidc"`
 	Security           Security   `mapstructure:"security"           json:"security"`
 	Onboarding         Onboarding `mapstructure:"onboarding"         json:"onboarding"`
+	WatchConfig        bool       `mapstructure:"watchConfig"        json:"watchConfig"`
+	Cache              Cache      `mapstructure:"cache"              json:"cache"`
+	Metrics            Metrics    `mapstructure:"metrics"            json:"metrics"`
 }
 
 func NewEnv() (*Env, error) {
 	env := Env{}
 
-	viper.SetConfigType("yaml")
+	// 🔹 A fresh instance, so that a reload starts over from the embedded defaults
+	v := viper.New()
+	v.SetConfigType("yaml")
 
-	if err := viper.ReadConfig(bytes.NewReader(defaultConfig)); err != nil {
+	if err := v.ReadConfig(bytes.NewReader(defaultConfig)); err != nil {
 		return nil, fmt.Errorf("failed to read embedded default config: %w", err)
 	} else {
 		slog.Info("Successfully loaded embedded default config")
 	}
 
-	viper.SetConfigFile("env.yaml")
-	viper.AddConfigPath(".") // Look in root directory
+	v.SetConfigFile(configFile)
+	v.AddConfigPath(".") // Look in root directory
 
 	// If `env.yaml` exists, merge it (it overrides embedded defaults)
-	if err := viper.MergeInConfig(); err == nil {
-		slog.Info("Loaded external config file", slog.String("file", "env.yaml"))
+	if err := v.MergeInConfig(); err == nil {
+		slog.Info("Loaded external config file", slog.String("file", configFile))
 	} else {
 		slog.Warn("No external config file found, using embedded defaults")
 	}
 
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	viper.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
 
-	if err := viper.Unmarshal(&env); err != nil {
+	if err := v.Unmarshal(&env); err != nil {
 		return nil, fmt.Errorf("failed to parse environment configuration: %w", err)
 	}
 
 	return &env, nil
 }
+
+// WatchEnv calls onChange with the environment loaded again each time env.yaml changes, including
+// when it is mounted from a ConfigMap that gets updated. Nothing is watched without env.yaml.
+func WatchEnv(onChange func(env *Env, err error)) {
+	v := viper.New()
+	v.SetConfigFile(configFile)
+
+	if err := v.ReadInConfig(); err != nil {
+		slog.Warn("⚠️ No external config file to watch, configuration reload is disabled")
+		return
+	}
+
+	v.OnConfigChange(func(e fsnotify.Event) {
+		slog.Info("🔹 Config file changed, reloading", slog.String("file", e.Name))
+		onChange(NewEnv())
+	})
+	v.WatchConfig()
+
+	slog.Info("✅ Watching config file for changes", slog.String("file", configFile))
+}
@@ -0,0 +1,28 @@
+package bootstrap
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// InitMetrics sets the global MeterProvider, exporting the OpenTelemetry metrics of the service,
+// e.g. the API requests and configuration reloads, in the Prometheus format. It returns the
+// handler serving them.
+func InitMetrics() (http.Handler, error) {
+	registry := prometheus.NewRegistry()
+
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Prometheus exporter: %w", err)
+	}
+
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter)))
+
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
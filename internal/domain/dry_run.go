@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+type dryRunKey struct{}
+
+// WithDryRun marks the context so that changes are validated by Kubernetes without being
+// persisted.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "text/template"
+
+// Manifest is a Go template rendering one or more Kubernetes objects, applied in every onboarded
+// namespace.
+type Manifest struct {
+	Name     string
+	Template *template.Template
+}
+
+type Manifests struct {
+	Enabled   bool
+	Region    string
+	Templates []Manifest
+}
+
+// ManifestData is the context manifest templates are rendered with.
+type ManifestData struct {
+	User      User
+	Namespace string
+	Group     string // empty for user namespaces
+	Region    string
+}
+
+// ManifestObject identifies an object applied from a manifest within a namespace.
+type ManifestObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
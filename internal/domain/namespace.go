@@ -1,5 +1,41 @@
 package domain
 
+import (
+	"errors"
+	"text/template"
+	"time"
+)
+
+// IdentityAnnotation records the user or group a namespace name was derived from, e.g.
+// "user:Jean.Dupont@insee.fr", so that two identities normalized to the same name are detected.
+const IdentityAnnotation = "onyxia.sh/identity"
+
+// RolesAnnotation records the roles of the owner of a user namespace at their last onboarding,
+// comma-separated, so that their quota can be reconciled while they are away.
+const RolesAnnotation = "onyxia.sh/roles"
+
+// LastLoginAnnotation holds the last login of the namespace owner, in unix milliseconds.
+const LastLoginAnnotation = "onyxia_last_login_timestamp"
+
+// FingerprintAnnotation holds a hash of the state applied by the last onboarding of a namespace:
+// its labels, annotations but the last login, quota and quota profile.
+const FingerprintAnnotation = "onyxia.sh/fingerprint"
+
+// ArchivedAtAnnotation holds when an archived namespace was offboarded, in RFC 3339.
+const ArchivedAtAnnotation = "onyxia.sh/archived-at"
+
+// ErrNamespaceCollision is returned when the namespace of an onboarding already exists and belongs
+// to another identity, or was not created by onboarding.
+var ErrNamespaceCollision = errors.New("namespace already belongs to another identity")
+
+// ErrNamespaceNotOwned is returned for namespaces the onboarding must not manage: reserved names,
+// and existing namespaces it did not create when offboarding or reaping.
+var ErrNamespaceNotOwned = errors.New("namespace is not managed by onboarding")
+
+// ErrFieldManagerConflict is returned when fields onboarding applies are managed by someone else,
+// e.g. a quota value edited with kubectl. They are left as they are.
+var ErrFieldManagerConflict = errors.New("fields are managed by another field manager")
+
 type Annotation struct {
 	Enabled bool
 	Static  map[string]string
@@ -11,6 +47,44 @@ type Annotation struct {
 type Namespace struct {
 	NamespacePrefix      string
 	GroupNamespacePrefix string
+	NameTemplate         *template.Template // takes precedence over NamespacePrefix when set
+	GroupNameTemplate    *template.Template // takes precedence over GroupNamespacePrefix when set
 	Annotation           Annotation
 	NamespaceLabels      map[string]string
+	DynamicLabels        []DynamicLabel
+	ReservedNames        []string // names or glob patterns, e.g. "kube-*"
+	// SkipIfOnboardedWithin skips onboardings of an unchanged namespace whose owner logged in
+	// more recently. Zero disables skipping.
+	SkipIfOnboardedWithin time.Duration
+}
+
+// DynamicLabel is a namespace label whose value is rendered from the onboarding request, with
+// the same data and functions as namespace name templates.
+type DynamicLabel struct {
+	Name     string
+	Template *template.Template
+}
+
+// NamespaceNameData is the context namespace name templates are rendered with.
+type NamespaceNameData struct {
+	Username string
+	Group    string // empty for user namespaces
+	Roles    []string
+	Groups   []string
+	Claims   map[string]any
+}
+
+// NamespaceStatus is the observed state of a namespace and of its onyxia-quota.
+type NamespaceStatus struct {
+	Name        string
+	Exists      bool
+	Annotations map[string]string
+	Labels      map[string]string
+	Quota       *QuotaStatus // nil when the namespace has no onyxia-quota
+}
+
+type QuotaStatus struct {
+	Hard    map[string]string
+	Used    map[string]string
+	Ignored bool // the quota carries the ignore annotation
 }
@@ -0,0 +1,12 @@
+package domain
+
+type NetworkPolicy struct {
+	Name string
+	Spec string // NetworkPolicySpec written in YAML
+}
+
+type NetworkPolicies struct {
+	Enabled bool
+	User    []NetworkPolicy
+	Group   []NetworkPolicy
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+type OffboardingMode string
+
+const (
+	OffboardingModeDelete  OffboardingMode = "delete"
+	OffboardingModeArchive OffboardingMode = "archive"
+)
+
+type Offboarding struct {
+	Enabled     bool
+	Mode        OffboardingMode
+	GracePeriod time.Duration
+}
@@ -5,11 +5,31 @@ import (
 )
 
 type OnboardingRequest struct {
-	Group     *string // Use pointer to indicate optional value
-	UserName  string
-	UserRoles []string
+	Group      *string // Use pointer to indicate optional value
+	UserName   string
+	UserRoles  []string
+	UserGroups []string
+	DryRun     bool
+}
+
+// OnboardingResult describes the namespace computed for an onboarding request.
+type OnboardingResult struct {
+	Namespace    string
+	Annotations  map[string]string
+	Labels       map[string]string
+	QuotaProfile string // empty when quotas are disabled
+	DryRun       bool
+	Skipped      bool // onboarded recently with the same state, nothing was applied
+}
+
+type OffboardingRequest struct {
+	Group    *string // Use pointer to indicate optional value
+	UserName string
 }
 
 type OnboardingUsecase interface {
-	Onboard(ctx context.Context, req OnboardingRequest) error
+	Onboard(ctx context.Context, req OnboardingRequest) (OnboardingResult, error)
+	Offboard(ctx context.Context, req OffboardingRequest) error
+	Status(ctx context.Context, req OnboardingRequest) (NamespaceStatus, error)
+	ReconcileQuotas(ctx context.Context) (QuotaReconcileSummary, error)
 }
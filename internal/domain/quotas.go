@@ -1,5 +1,7 @@
 package domain
 
+import "regexp"
+
 type Quota struct {
 	MemoryRequest           string
 	CPURequest              string
@@ -11,14 +13,74 @@ type Quota struct {
 	EphemeralStorageLimit   string
 	GPURequest              string
 	GPULimit                string
+	Resources               map[string]string // other resources, e.g. "amd.com/gpu"
+	Scoped                  []ScopedQuota
+	LimitRange              LimitRange
+}
+
+// ScopedQuota is an additional ResourceQuota, named onyxia-quota-<Name>, that only tracks the
+// pods matching its scopes, e.g. a GPU quota for pods of a given priority class.
+type ScopedQuota struct {
+	Name          string
+	Scopes        []string // BestEffort, NotBestEffort, Terminating, NotTerminating, ...
+	ScopeSelector []ScopeSelectorRequirement
+	Hard          map[string]string
+}
+
+type ScopeSelectorRequirement struct {
+	ScopeName string
+	Operator  string // In, NotIn, Exists or DoesNotExist
+	Values    []string
+}
+
+// LimitRange holds the container defaults and maximums applied next to a quota, so that pods
+// without explicit requests or limits are still admitted.
+type LimitRange struct {
+	DefaultCPU           string
+	DefaultMemory        string
+	DefaultRequestCPU    string
+	DefaultRequestMemory string
+	MaxCPU               string
+	MaxMemory            string
 }
 
+// GroupQuota is the quota of the groups matching either Name, the glob Pattern or Regex.
+type GroupQuota struct {
+	Name    string
+	Pattern string
+	Regex   *regexp.Regexp
+	Quota   Quota
+}
+
+// RoleMergeStrategy selects the quota of a user having several roles with a quota.
+type RoleMergeStrategy string
+
+const (
+	RoleMergeStrategyFirst    RoleMergeStrategy = "first"    // first role of the token
+	RoleMergeStrategyPriority RoleMergeStrategy = "priority" // first role of RolePriority
+	RoleMergeStrategyMax      RoleMergeStrategy = "max"      // maximum of each resource
+	RoleMergeStrategySum      RoleMergeStrategy = "sum"      // sum of each resource
+)
+
+// QuotaRemovalPolicy tells what to do with the managed quota of a namespace when the
+// configuration no longer defines one, because quotas are disabled or the profile has no limits.
+type QuotaRemovalPolicy string
+
+const (
+	QuotaRemovalPolicyKeep   QuotaRemovalPolicy = "keep"   // leave the existing quota as it is
+	QuotaRemovalPolicyDelete QuotaRemovalPolicy = "delete" // delete the quota if it is managed
+)
+
 type Quotas struct {
-	Enabled      bool
-	Default      Quota
-	UserEnabled  bool
-	User         Quota
-	GroupEnabled bool
-	Group        Quota
-	Roles        map[string]Quota
+	Enabled           bool
+	Default           Quota
+	UserEnabled       bool
+	User              Quota
+	GroupEnabled      bool
+	Group             Quota
+	Groups            []GroupQuota // consulted in order before Group
+	Roles             map[string]Quota
+	RoleMergeStrategy RoleMergeStrategy
+	RolePriority      []string
+	RemovalPolicy     QuotaRemovalPolicy
 }
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// MemberSeenAtAnnotation holds when the member of a RoleBinding in members mode last onboarded
+// into its group namespace, in RFC 3339 truncated to the day.
+const MemberSeenAtAnnotation = "onyxia.sh/rbac-member-seen-at"
+
+const (
+	SubjectKindUser  = "User"
+	SubjectKindGroup = "Group"
+)
+
+type GroupRBACMode string
+
+const (
+	// GroupRBACModeGroup binds the OIDC group itself, the API server resolves membership.
+	GroupRBACModeGroup GroupRBACMode = "group"
+	// GroupRBACModeMembers binds each member seen at login and prunes the ones not seen for
+	// MemberExpiry.
+	GroupRBACModeMembers GroupRBACMode = "members"
+)
+
+type GroupRBAC struct {
+	Enabled         bool
+	Mode            GroupRBACMode
+	RoleBindingName string
+	ClusterRole     string
+	GroupPrefix     string
+	Roles           map[string]string // group name -> ClusterRole
+	MemberExpiry    time.Duration
+}
+
+type RBAC struct {
+	Enabled         bool
+	RoleBindingName string
+	ClusterRole     string
+	UsernamePrefix  string
+	Groups          GroupRBAC
+}
+
+type Subject struct {
+	Kind string
+	Name string
+}
+
+type RoleBinding struct {
+	Name        string
+	ClusterRole string
+	Subjects    []Subject
+	Labels      map[string]string
+	Annotations map[string]string
+}
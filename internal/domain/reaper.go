@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type ReaperAction string
+
+const (
+	ReaperActionWarn      ReaperAction = "warn"
+	ReaperActionScaleDown ReaperAction = "scale_down"
+	ReaperActionArchive   ReaperAction = "archive"
+	ReaperActionDelete    ReaperAction = "delete"
+	ReaperActionPurge     ReaperAction = "purge" // delete an archive past its grace period
+)
+
+// Reaper acts on namespaces whose owner has not logged in for a while.
+// A zero threshold disables the corresponding action.
+type Reaper struct {
+	Enabled        bool
+	ReportOnly     bool // only log the actions that would be taken
+	Interval       time.Duration
+	WarnAfter      time.Duration
+	ScaleDownAfter time.Duration
+	ArchiveAfter   time.Duration
+	DeleteAfter    time.Duration
+	GracePeriod    time.Duration // grace period of archived namespaces
+	LeaseName      string
+	LeaseNamespace string
+	// NamespaceLabels and ReservedNames select the namespaces of the onboarding, see Namespace
+	NamespaceLabels map[string]string
+	ReservedNames   []string
+}
+
+// IdleNamespace is an onboarded namespace along with the actions already taken on it.
+type IdleNamespace struct {
+	Name       string
+	LastLogin  time.Time
+	Warned     bool
+	ScaledDown bool
+	Archived   bool
+	PurgeAfter time.Time // end of the grace period of an archived namespace
+}
+
+type ReaperUsecase interface {
+	Run(ctx context.Context)
+	Reap(ctx context.Context) error
+}
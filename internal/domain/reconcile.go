@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Reconcile periodically applies the current quota profiles to every onboarded namespace, so
+// that configuration changes reach users who do not onboard again.
+type Reconcile struct {
+	Enabled        bool
+	Interval       time.Duration
+	LeaseName      string
+	LeaseNamespace string
+}
+
+// ManagedNamespace is a namespace carrying the labels set by the onboarding.
+type ManagedNamespace struct {
+	Name        string
+	Annotations map[string]string
+	Archived    bool
+}
+
+// QuotaReconcileSummary counts the namespaces of a quota reconciliation by result.
+type QuotaReconcileSummary struct {
+	Namespaces int
+	Created    int
+	Updated    int
+	Unchanged  int
+	Ignored    int // fully or partially
+	Deleted    int
+	Conflicts  int // fields managed by another field manager
+	Skipped    int // archived, or quotas disabled
+	Unknown    int // owner or roles unknown, onboarded by a previous version
+	Failed     int
+	Pruned     int // expired group member RoleBindings
+}
+
+func (s QuotaReconcileSummary) String() string {
+	return fmt.Sprintf(
+		"namespaces=%d created=%d updated=%d unchanged=%d ignored=%d deleted=%d "+
+			"conflicts=%d skipped=%d unknown=%d failed=%d pruned=%d",
+		s.Namespaces,
+		s.Created,
+		s.Updated,
+		s.Unchanged,
+		s.Ignored,
+		s.Deleted,
+		s.Conflicts,
+		s.Skipped,
+		s.Unknown,
+		s.Failed,
+		s.Pruned,
+	)
+}
+
+type QuotaReconcilerUsecase interface {
+	Run(ctx context.Context)
+}
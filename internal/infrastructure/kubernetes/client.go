@@ -8,14 +8,20 @@ import (
 	"path/filepath"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 )
 
 type KubernetesClient struct {
-	Clientset *kubernetes.Clientset
+	Clientset     *kubernetes.Clientset
+	DynamicClient *dynamic.DynamicClient
+	RESTMapper    meta.RESTMapper
 }
 
 func NewKubernetesClient() (*KubernetesClient, error) {
@@ -42,12 +48,23 @@ func NewKubernetesClient() (*KubernetesClient, error) {
 		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes dynamic client: %w", err)
+	}
+
 	err = checkConnectivity(clientset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Kubernetes API server: %w", err)
 	}
 
-	return &KubernetesClient{Clientset: clientset}, nil
+	return &KubernetesClient{
+		Clientset:     clientset,
+		DynamicClient: dynamicClient,
+		RESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(
+			memory.NewMemCacheClient(clientset.Discovery()),
+		),
+	}, nil
 }
 
 func checkConnectivity(clientSet *kubernetes.Clientset) error {
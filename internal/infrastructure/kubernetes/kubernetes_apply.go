@@ -0,0 +1,203 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/csaupgrade"
+)
+
+// FieldManager owns the fields of namespaces and quotas set by the onboarding with server-side
+// apply. Fields owned by other managers are left as they are.
+const FieldManager string = "onyxia-onboarding"
+
+// The markers the service sets on namespaces outside of the onboarding are applied by their own
+// field managers, so that the next apply of FieldManager, which does not set them, keeps them.
+const (
+	ManifestsFieldManager   string = FieldManager + "-manifests"
+	OffboardingFieldManager string = FieldManager + "-offboarding"
+	ReaperFieldManager      string = FieldManager + "-reaper"
+)
+
+type patchFunc[T any] func(
+	ctx context.Context,
+	name string,
+	pt types.PatchType,
+	data []byte,
+	opts metav1.PatchOptions,
+	subresources ...string,
+) (T, error)
+
+// legacyManagers returns the configured managers found in the Update entries of the managed
+// fields of object. Versions of the service prior to server-side apply wrote with Update
+// requests, under a manager the API server named after their binary.
+func legacyManagers(object metav1.Object, configured sets.Set[string]) sets.Set[string] {
+	managers := sets.New[string]()
+	for _, entry := range object.GetManagedFields() {
+		if entry.Operation == metav1.ManagedFieldsOperationUpdate && configured.Has(entry.Manager) {
+			managers.Insert(entry.Manager)
+		}
+	}
+	return managers
+}
+
+// upgradeFieldManager hands the fields set by legacy field managers over to FieldManager, so
+// that applying them again does not conflict with the service itself.
+func upgradeFieldManager[T interface {
+	runtime.Object
+	metav1.Object
+}, R any](
+	ctx context.Context,
+	obj T,
+	configured sets.Set[string],
+	patch patchFunc[R],
+) error {
+	managers := legacyManagers(obj, configured)
+	if managers.Len() == 0 {
+		return nil
+	}
+
+	name := obj.GetName()
+	slog.InfoContext(ctx, "🔹 Handing fields of legacy field managers over",
+		slog.String("name", name),
+		slog.Any("managers", sets.List(managers)),
+	)
+
+	data, err := csaupgrade.UpgradeManagedFieldsPatch(obj, managers, FieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade field managers of %s: %w", name, err)
+	}
+	if data == nil {
+		return nil
+	}
+
+	_, err = patch(ctx, name, types.JSONPatchType, data, metav1.PatchOptions{DryRun: dryRun(ctx)})
+	if err != nil {
+		return fmt.Errorf("failed to upgrade field managers of %s: %w", name, err)
+	}
+	return nil
+}
+
+// applyNamespaceMetadata applies labels and annotations to an existing namespace under manager,
+// removing those it set before and are not part of them anymore. They are markers of the
+// service, so conflicts are forced.
+func applyNamespaceMetadata(
+	ctx context.Context,
+	clientset k8s.Interface,
+	name string,
+	manager string,
+	labels map[string]string,
+	annotations map[string]string,
+) error {
+	namespace := corev1ac.Namespace(name)
+	if len(labels) > 0 {
+		namespace.WithLabels(labels)
+	}
+	if len(annotations) > 0 {
+		namespace.WithAnnotations(annotations)
+	}
+
+	_, err := clientset.CoreV1().Namespaces().Apply(ctx, namespace, metav1.ApplyOptions{
+		FieldManager: manager,
+		Force:        true,
+		DryRun:       dryRun(ctx),
+	})
+	return err
+}
+
+// applyOptions are the options of the apply requests of the onboarding. Conflicts are not
+// forced, so that fields changed by another manager are reported instead of overwritten.
+func applyOptions(ctx context.Context) metav1.ApplyOptions {
+	return metav1.ApplyOptions{FieldManager: FieldManager, DryRun: dryRun(ctx)}
+}
+
+// createOptions and updateOptions are the options of the objects the onboarding writes without
+// server-side apply.
+func createOptions(ctx context.Context) metav1.CreateOptions {
+	return metav1.CreateOptions{FieldManager: FieldManager, DryRun: dryRun(ctx)}
+}
+
+func updateOptions(ctx context.Context) metav1.UpdateOptions {
+	return metav1.UpdateOptions{FieldManager: FieldManager, DryRun: dryRun(ctx)}
+}
+
+// conflictError returns the error of an apply request that conflicted with other managers,
+// listing the fields they own.
+func conflictError(kind string, name string, err error) error {
+	return fmt.Errorf(
+		"%w: %s %s: %s",
+		domain.ErrFieldManagerConflict,
+		kind,
+		name,
+		strings.Join(conflictingFields(err), ", "),
+	)
+}
+
+// conflictingFields returns the fields an apply request conflicted on, along with their manager.
+func conflictingFields(err error) []string {
+	var statusErr *apierrors.StatusError
+	if !errors.As(err, &statusErr) || statusErr.ErrStatus.Details == nil {
+		return nil
+	}
+
+	var fields []string
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			fields = append(fields, cause.Field+" ("+cause.Message+")")
+		}
+	}
+	return fields
+}
+
+// isApplied tells whether the last apply of FieldManager set exactly the given annotations and
+// labels, and the object still holds their values, in which case applying them is a no-op.
+func isApplied(object metav1.Object, annotations, labels map[string]string) bool {
+	for _, entry := range object.GetManagedFields() {
+		if entry.Manager != FieldManager ||
+			entry.Operation != metav1.ManagedFieldsOperationApply ||
+			entry.FieldsV1 == nil {
+			continue
+		}
+
+		var fields struct {
+			Metadata struct {
+				Annotations map[string]any `json:"f:annotations"`
+				Labels      map[string]any `json:"f:labels"`
+			} `json:"f:metadata"`
+		}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			return false
+		}
+
+		return holds(object.GetAnnotations(), fields.Metadata.Annotations, annotations) &&
+			holds(object.GetLabels(), fields.Metadata.Labels, labels)
+	}
+	return false
+}
+
+// holds tells whether the owned keys of a map are the desired ones, with the desired values.
+func holds(current map[string]string, owned map[string]any, desired map[string]string) bool {
+	if len(owned) != len(desired) {
+		return false
+	}
+	for key := range owned {
+		value, ok := desired[strings.TrimPrefix(key, "f:")]
+		if !ok || current[strings.TrimPrefix(key, "f:")] != value {
+			return false
+		}
+	}
+	return true
+}
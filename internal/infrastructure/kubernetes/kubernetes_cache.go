@@ -0,0 +1,129 @@
+package kubernetes
+
+import (
+	"context"
+	"log/slog"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	k8s "k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceCache watches the namespaces and resource quotas created by the onboarding, so that
+// their state is read from memory rather than from the API server.
+type NamespaceCache struct {
+	factory    informers.SharedInformerFactory
+	namespaces corev1listers.NamespaceLister
+	quotas     corev1listers.ResourceQuotaLister
+	synced     []cache.InformerSynced
+}
+
+func NewNamespaceCache(clientset k8s.Interface) *NamespaceCache {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		clientset,
+		0,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = labels.SelectorFromSet(managedLabels()).String()
+		}),
+	)
+
+	namespaces := factory.Core().V1().Namespaces()
+	quotas := factory.Core().V1().ResourceQuotas()
+	_ = namespaces.Informer().SetWatchErrorHandlerWithContext(watchErrorHandler("namespaces"))
+	_ = quotas.Informer().SetWatchErrorHandlerWithContext(watchErrorHandler("resourcequotas"))
+
+	return &NamespaceCache{
+		factory:    factory,
+		namespaces: namespaces.Lister(),
+		quotas:     quotas.Lister(),
+		synced: []cache.InformerSynced{
+			namespaces.Informer().HasSynced,
+			quotas.Informer().HasSynced,
+		},
+	}
+}
+
+// watchErrorHandler reports the missing permissions that keep the cache from syncing: until it is
+// synced, objects are read from the API server.
+func watchErrorHandler(resource string) cache.WatchErrorHandlerWithContext {
+	return func(ctx context.Context, r *cache.Reflector, err error) {
+		if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) {
+			slog.ErrorContext(ctx, "❌ Namespace cache cannot watch, reading from the API server",
+				slog.String("resource", resource),
+				slog.Any("error", err),
+			)
+			return
+		}
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+	}
+}
+
+// Start watches namespaces and quotas until ctx is cancelled.
+func (c *NamespaceCache) Start(ctx context.Context) {
+	c.factory.Start(ctx.Done())
+}
+
+// WaitForSync blocks until the cache is synced or ctx is cancelled, and tells whether it is.
+func (c *NamespaceCache) WaitForSync(ctx context.Context) bool {
+	return cache.WaitForCacheSync(ctx.Done(), c.synced...)
+}
+
+// HasSynced tells whether the initial list of namespaces and quotas is in the cache. A nil cache
+// is never synced.
+func (c *NamespaceCache) HasSynced() bool {
+	if c == nil {
+		return false
+	}
+	for _, synced := range c.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// namespace returns a copy of a cached namespace, or nil when it is not in the cache: it may
+// exist without the onboarding labels.
+func (c *NamespaceCache) namespace(name string) *v1.Namespace {
+	namespace, err := c.namespaces.Get(name)
+	if err != nil {
+		return nil
+	}
+	return namespace.DeepCopy()
+}
+
+// quota returns a copy of a cached resource quota, or nil when it is not in the cache.
+func (c *NamespaceCache) quota(namespace, name string) *v1.ResourceQuota {
+	quota, err := c.quotas.ResourceQuotas(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	return quota.DeepCopy()
+}
+
+// namespaceQuotas returns copies of the cached resource quotas of a namespace.
+func (c *NamespaceCache) namespaceQuotas(namespace string) []v1.ResourceQuota {
+	quotas, _ := c.quotas.ResourceQuotas(namespace).List(labels.Everything())
+
+	result := make([]v1.ResourceQuota, 0, len(quotas))
+	for _, quota := range quotas {
+		result = append(result, *quota.DeepCopy())
+	}
+	return result
+}
+
+// listNamespaces returns copies of the cached namespaces matching selector.
+func (c *NamespaceCache) listNamespaces(selector labels.Selector) []v1.Namespace {
+	namespaces, _ := c.namespaces.List(selector)
+
+	result := make([]v1.Namespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		result = append(result, *namespace.DeepCopy())
+	}
+	return result
+}
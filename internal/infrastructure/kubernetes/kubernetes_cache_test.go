@@ -0,0 +1,86 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func startCache(t *testing.T, clientset *fake.Clientset) *NamespaceCache {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cache := NewNamespaceCache(clientset)
+	cache.Start(ctx)
+	assert.True(t, cache.WaitForSync(ctx))
+	return cache
+}
+
+// ✅ Test: Only Objects Created by Onboarding are Cached
+func TestNamespaceCache(t *testing.T) {
+	clientset := fake.NewClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "user-jdoe", Labels: managedLabels()}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	)
+
+	var nilCache *NamespaceCache
+	assert.False(t, nilCache.HasSynced())
+
+	cache := startCache(t, clientset)
+
+	assert.True(t, cache.HasSynced())
+	assert.NotNil(t, cache.namespace("user-jdoe"))
+	assert.Nil(t, cache.namespace("kube-system"))
+}
+
+// ✅ Test: Unchanged Namespace is Detected from Cache Without API Calls
+func TestCreateNamespace_CachedUnchanged(t *testing.T) {
+	clientset := fake.NewClientset()
+	annotations := map[string]string{domain.IdentityAnnotation: "user:jdoe"}
+	labels := map[string]string{"onyxia.sh/department": "dg75"}
+
+	_, err := NewKubernetesNamespaceService(clientset).
+		CreateNamespace(context.Background(), "user-jdoe", annotations, labels)
+	assert.NoError(t, err)
+
+	service := NewCachedKubernetesNamespaceService(clientset, startCache(t, clientset), nil)
+	clientset.ClearActions()
+
+	result, err := service.CreateNamespace(context.Background(), "user-jdoe", annotations, labels)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAlreadyExists, result)
+	assert.Empty(t, clientset.Actions())
+
+	// 🔹 A removed annotation is a change
+	result, err = service.CreateNamespace(context.Background(), "user-jdoe", nil, labels)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
+	assert.Len(t, clientset.Actions(), 1)
+}
+
+// ✅ Test: Unchanged Quota is Detected from Cache Without API Calls
+func TestApplyResourceQuotas_CachedUnchanged(t *testing.T) {
+	clientset := fake.NewClientset()
+	quota := &domain.Quota{CPURequest: "4"}
+
+	_, err := NewKubernetesNamespaceService(clientset).
+		ApplyResourceQuotas(context.Background(), "user-jdoe", quota)
+	assert.NoError(t, err)
+
+	service := NewCachedKubernetesNamespaceService(clientset, startCache(t, clientset), nil)
+	clientset.ClearActions()
+
+	result, err := service.ApplyResourceQuotas(context.Background(), "user-jdoe", quota)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaUnchanged, result)
+	assert.Empty(t, clientset.Actions())
+}
@@ -0,0 +1,209 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+const IdleWarningAnnotation string = "onyxia.sh/idle-warning-at"
+const ScaledDownAnnotation string = "onyxia.sh/scaled-down-at"
+
+var scaleToZeroPatch = []byte(`{"spec":{"replicas":0}}`)
+
+type KubernetesIdleNamespaceService struct {
+	clientset k8s.Interface
+	now       func() time.Time
+}
+
+func NewKubernetesIdleNamespaceService(clientset k8s.Interface) interfaces.IdleNamespaceService {
+	return &KubernetesIdleNamespaceService{
+		clientset: clientset,
+		now:       time.Now,
+	}
+}
+
+func (s *KubernetesIdleNamespaceService) ListIdleNamespaces(
+	ctx context.Context,
+	selector map[string]string,
+) ([]domain.IdleNamespace, error) {
+	namespaces, err := s.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Merge(selector, managedLabels()).AsSelector().String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	var result []domain.IdleNamespace
+	for _, namespace := range namespaces.Items {
+		if namespace.Annotations[IgnoreAnnotation] == "true" {
+			continue
+		}
+
+		archived := namespace.Labels[ArchivedLabel] == "true"
+		// 🔹 Archives are purged after their grace period, whether their owner logged in or not
+		var purgeAfter time.Time
+		if archived {
+			purgeAfter, _ = time.Parse(time.RFC3339, namespace.Annotations[PurgeAfterAnnotation])
+		}
+
+		var lastLogin time.Time
+		value, ok := namespace.Annotations[domain.LastLoginAnnotation]
+		if !ok && purgeAfter.IsZero() {
+			continue
+		}
+		if ok {
+			millis, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				slog.WarnContext(ctx, "⚠️ Invalid last login annotation, skipping namespace",
+					slog.String("namespace", namespace.Name),
+					slog.String("value", value),
+				)
+				continue
+			}
+			lastLogin = time.UnixMilli(millis)
+		}
+
+		result = append(result, domain.IdleNamespace{
+			Name:       namespace.Name,
+			LastLogin:  lastLogin,
+			Warned:     happenedSince(namespace.Annotations[IdleWarningAnnotation], lastLogin),
+			ScaledDown: happenedSince(namespace.Annotations[ScaledDownAnnotation], lastLogin),
+			Archived:   archived,
+			PurgeAfter: purgeAfter,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *KubernetesIdleNamespaceService) WarnNamespace(ctx context.Context, namespace string) error {
+	return s.annotateNamespace(ctx, namespace, IdleWarningAnnotation)
+}
+
+func (s *KubernetesIdleNamespaceService) ScaleDownNamespace(
+	ctx context.Context,
+	namespace string,
+) (int, error) {
+	scaled := 0
+
+	if _, err := s.getManagedNamespace(ctx, namespace); err != nil {
+		return scaled, err
+	}
+
+	deployments, err := s.clientset.AppsV1().
+		Deployments(namespace).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return scaled, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, deployment := range deployments.Items {
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+			continue
+		}
+		_, err := s.clientset.AppsV1().Deployments(namespace).Patch(
+			ctx,
+			deployment.Name,
+			types.MergePatchType,
+			scaleToZeroPatch,
+			metav1.PatchOptions{FieldManager: ReaperFieldManager},
+		)
+		if err != nil {
+			return scaled, fmt.Errorf("failed to scale down deployment %s: %w", deployment.Name, err)
+		}
+		scaled++
+	}
+
+	statefulSets, err := s.clientset.AppsV1().
+		StatefulSets(namespace).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return scaled, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, statefulSet := range statefulSets.Items {
+		if statefulSet.Spec.Replicas != nil && *statefulSet.Spec.Replicas == 0 {
+			continue
+		}
+		_, err := s.clientset.AppsV1().StatefulSets(namespace).Patch(
+			ctx,
+			statefulSet.Name,
+			types.MergePatchType,
+			scaleToZeroPatch,
+			metav1.PatchOptions{FieldManager: ReaperFieldManager},
+		)
+		if err != nil {
+			return scaled, fmt.Errorf(
+				"failed to scale down statefulset %s: %w",
+				statefulSet.Name,
+				err,
+			)
+		}
+		scaled++
+	}
+
+	return scaled, s.annotateNamespace(ctx, namespace, ScaledDownAnnotation)
+}
+
+// annotateNamespace records when an action was taken on a namespace. The reaper annotations are
+// applied together, as an apply removes the ones it leaves out.
+func (s *KubernetesIdleNamespaceService) annotateNamespace(
+	ctx context.Context,
+	namespace string,
+	annotation string,
+) error {
+	existing, err := s.getManagedNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	annotations := make(map[string]string)
+	for _, key := range []string{IdleWarningAnnotation, ScaledDownAnnotation} {
+		if value, ok := existing.Annotations[key]; ok {
+			annotations[key] = value
+		}
+	}
+	annotations[annotation] = s.now().UTC().Format(time.RFC3339)
+
+	err = applyNamespaceMetadata(ctx, s.clientset, namespace, ReaperFieldManager, nil, annotations)
+	if err != nil {
+		return fmt.Errorf("failed to annotate namespace: %w", err)
+	}
+
+	return nil
+}
+
+// getManagedNamespace returns the namespace, unless it was not created by the onboarding.
+func (s *KubernetesIdleNamespaceService) getManagedNamespace(
+	ctx context.Context,
+	name string,
+) (*v1.Namespace, error) {
+	namespace, err := s.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	if !isManaged(namespace.Labels) {
+		return nil, fmt.Errorf("%w: namespace %s was not created by onboarding",
+			domain.ErrNamespaceNotOwned, name)
+	}
+	return namespace, nil
+}
+
+// happenedSince reports whether the RFC3339 timestamp is not older than since.
+// Actions taken before the last login are stale: the namespace has been used since.
+func happenedSince(timestamp string, since time.Time) bool {
+	at, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return false
+	}
+	return !at.Before(since.Truncate(time.Second))
+}
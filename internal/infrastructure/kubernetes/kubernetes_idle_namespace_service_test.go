@@ -0,0 +1,192 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func onboardedNamespace(name string, lastLogin time.Time) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   name,
+		Labels: map[string]string{"created-by": "onyxia", "team": "a"},
+		Annotations: map[string]string{
+			"onyxia_last_login_timestamp": fmt.Sprint(lastLogin.UnixMilli()),
+		},
+	}}
+}
+
+// ✅ Test: List Onboarded Namespaces Matching the Selector with a Last Login
+func TestListIdleNamespaces(t *testing.T) {
+	lastLogin := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	warned := onboardedNamespace("user-warned", lastLogin)
+	warned.Annotations[IdleWarningAnnotation] = "2025-02-01T00:00:00Z"
+	warned.Annotations[ScaledDownAnnotation] = "2024-12-01T00:00:00Z" // before the last login
+	warned.Labels[ArchivedLabel] = "true"
+
+	ignored := onboardedNamespace("user-ignored", lastLogin)
+	ignored.Annotations[IgnoreAnnotation] = "true"
+
+	otherTeam := onboardedNamespace("user-other-team", lastLogin)
+	otherTeam.Labels["team"] = "b"
+
+	notOnboarded := onboardedNamespace("kube-system", lastLogin)
+	notOnboarded.Labels = nil
+
+	clientset := fake.NewClientset(warned, ignored, otherTeam, notOnboarded)
+	service := NewKubernetesIdleNamespaceService(clientset)
+
+	namespaces, err := service.ListIdleNamespaces(
+		context.Background(),
+		map[string]string{"team": "a"},
+	)
+
+	assert.NoError(t, err)
+	assert.Len(t, namespaces, 1)
+	assert.Equal(t, "user-warned", namespaces[0].Name)
+	assert.True(t, namespaces[0].LastLogin.Equal(lastLogin))
+	assert.True(t, namespaces[0].Warned)
+	assert.False(t, namespaces[0].ScaledDown)
+	assert.True(t, namespaces[0].Archived)
+}
+
+// ✅ Test: Archived Namespaces are Listed with their Purge Date, even without a Last Login
+func TestListIdleNamespaces_Archived(t *testing.T) {
+	clientset := fake.NewClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-offboarded",
+			Labels:      labelsWithOwnership(map[string]string{ArchivedLabel: "true"}),
+			Annotations: map[string]string{PurgeAfterAnnotation: "2025-02-01T00:00:00Z"},
+		}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-restored",
+			Labels:      managedLabels(),
+			Annotations: map[string]string{PurgeAfterAnnotation: "2025-02-01T00:00:00Z"},
+		}},
+	)
+	service := NewKubernetesIdleNamespaceService(clientset)
+
+	namespaces, err := service.ListIdleNamespaces(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, namespaces, 1)
+	assert.Equal(t, "user-offboarded", namespaces[0].Name)
+	assert.True(t, namespaces[0].LastLogin.IsZero())
+	assert.True(t, namespaces[0].Archived)
+	assert.True(t, namespaces[0].PurgeAfter.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+// ✅ Test: Warn Namespace
+func TestWarnNamespace(t *testing.T) {
+	clientset := fake.NewClientset(onboardedNamespace("user-test", time.Now()))
+	service := &KubernetesIdleNamespaceService{
+		clientset: clientset,
+		now:       func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) },
+	}
+
+	err := service.WarnNamespace(context.Background(), "user-test")
+
+	assert.NoError(t, err)
+	namespace, _ := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "user-test", metav1.GetOptions{})
+	assert.Equal(t, "2025-03-01T12:00:00Z", namespace.Annotations[IdleWarningAnnotation])
+}
+
+// ✅ Test: Scale Down Workloads
+func TestScaleDownNamespace(t *testing.T) {
+	clientset := fake.NewClientset(
+		onboardedNamespace("user-test", time.Now()),
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "jupyter", Namespace: "user-test"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "stopped", Namespace: "user-test"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](0)},
+		},
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "user-test"},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+		},
+	)
+	service := NewKubernetesIdleNamespaceService(clientset)
+
+	scaled, err := service.ScaleDownNamespace(context.Background(), "user-test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, scaled)
+
+	deployment, _ := clientset.AppsV1().
+		Deployments("user-test").
+		Get(context.Background(), "jupyter", metav1.GetOptions{})
+	assert.Equal(t, int32(0), *deployment.Spec.Replicas)
+
+	statefulSet, _ := clientset.AppsV1().
+		StatefulSets("user-test").
+		Get(context.Background(), "postgres", metav1.GetOptions{})
+	assert.Equal(t, int32(0), *statefulSet.Spec.Replicas)
+
+	namespace, _ := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "user-test", metav1.GetOptions{})
+	assert.NotEmpty(t, namespace.Annotations[ScaledDownAnnotation])
+}
+
+// ❌ Test: Failure When Scaling Down
+func TestScaleDownNamespace_Failure(t *testing.T) {
+	clientset := fake.NewClientset(
+		onboardedNamespace("user-test", time.Now()),
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "jupyter", Namespace: "user-test"},
+		},
+	)
+	clientset.PrependReactor("patch", "deployments",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to patch deployment")
+		})
+	service := NewKubernetesIdleNamespaceService(clientset)
+
+	_, err := service.ScaleDownNamespace(context.Background(), "user-test")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to scale down deployment jupyter")
+}
+
+// ❌ Test: Namespaces not Created by Onboarding are neither Warned nor Scaled Down
+func TestIdleActions_NotOwned(t *testing.T) {
+	namespace := onboardedNamespace("kube-system", time.Now())
+	namespace.Labels = nil
+	clientset := fake.NewClientset(
+		namespace,
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: "kube-system"},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		},
+	)
+	service := NewKubernetesIdleNamespaceService(clientset)
+
+	err := service.WarnNamespace(context.Background(), "kube-system")
+	assert.ErrorIs(t, err, domain.ErrNamespaceNotOwned)
+
+	_, err = service.ScaleDownNamespace(context.Background(), "kube-system")
+	assert.ErrorIs(t, err, domain.ErrNamespaceNotOwned)
+
+	deployment, _ := clientset.AppsV1().
+		Deployments("kube-system").
+		Get(context.Background(), "coredns", metav1.GetOptions{})
+	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+}
@@ -0,0 +1,341 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const ManagedByLabel string = "app.kubernetes.io/managed-by"
+const ManagedByValue string = "onyxia-onboarding"
+
+// ManifestsAnnotation lists, on the namespace, the objects applied from manifests during the last
+// onboarding. It is used to prune objects whose template has been removed.
+const ManifestsAnnotation string = "onyxia.sh/manifests"
+
+type KubernetesManifestService struct {
+	clientset     kubernetes.Interface
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+}
+
+var _ interfaces.ManifestService = (*KubernetesManifestService)(nil)
+
+func NewKubernetesManifestService(
+	clientset kubernetes.Interface,
+	dynamicClient dynamic.Interface,
+	mapper meta.RESTMapper,
+) *KubernetesManifestService {
+	return &KubernetesManifestService{
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		mapper:        mapper,
+	}
+}
+
+func (s *KubernetesManifestService) ApplyManifest(
+	ctx context.Context,
+	namespace string,
+	manifest string,
+) ([]interfaces.AppliedManifestObject, error) {
+	objects, err := decodeManifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding manifest: %w", err)
+	}
+
+	applied := make([]interfaces.AppliedManifestObject, 0, len(objects))
+	for _, object := range objects {
+		result, err := s.applyObject(ctx, namespace, object)
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, interfaces.AppliedManifestObject{
+			Object: domain.ManifestObject{
+				APIVersion: object.GetAPIVersion(),
+				Kind:       object.GetKind(),
+				Name:       object.GetName(),
+			},
+			Result: result,
+		})
+	}
+
+	return applied, nil
+}
+
+func (s *KubernetesManifestService) applyObject(
+	ctx context.Context,
+	namespace string,
+	desired *unstructured.Unstructured,
+) (interfaces.ManifestApplicationResult, error) {
+	resourceClient, err := s.resourceClient(namespace, desired.GroupVersionKind())
+	if err != nil {
+		return "", err
+	}
+
+	desired.SetNamespace(namespace)
+	desired.SetLabels(labels.Merge(desired.GetLabels(), manifestLabels()))
+
+	existing, err := resourceClient.Get(ctx, desired.GetName(), metav1.GetOptions{})
+
+	if errors.IsNotFound(err) {
+		_, err = resourceClient.Create(ctx, desired, metav1.CreateOptions{
+			FieldManager: ManifestsFieldManager,
+			DryRun:       dryRun(ctx),
+		})
+		if err != nil {
+			return "", fmt.Errorf(
+				"failed to create %s %s: %w",
+				desired.GetKind(),
+				desired.GetName(),
+				err,
+			)
+		}
+		return interfaces.ManifestCreated, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf(
+			"unexpected error checking for existing %s %s: %w",
+			desired.GetKind(),
+			desired.GetName(),
+			err,
+		)
+	}
+
+	if ignore, ok := existing.GetAnnotations()[IgnoreAnnotation]; ok && ignore == "true" {
+		return interfaces.ManifestIgnored, nil
+	}
+
+	// 🔹 Fields defaulted by the API server are not part of the manifest, only compare ours
+	if isSubset(desired.Object, existing.Object) {
+		return interfaces.ManifestUnchanged, nil
+	}
+
+	patchBytes, err := json.Marshal(desired.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal patch data: %w", err)
+	}
+
+	_, err = resourceClient.Patch(
+		ctx,
+		desired.GetName(),
+		types.MergePatchType,
+		patchBytes,
+		metav1.PatchOptions{FieldManager: ManifestsFieldManager, DryRun: dryRun(ctx)},
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to update %s %s: %w",
+			desired.GetKind(),
+			desired.GetName(),
+			err,
+		)
+	}
+
+	return interfaces.ManifestUpdated, nil
+}
+
+// PruneManifests deletes the objects applied during the previous onboarding of the namespace that
+// are not part of keep anymore, then records keep as the new inventory. Objects marked as ignored
+// or no longer managed by onyxia are left untouched.
+func (s *KubernetesManifestService) PruneManifests(
+	ctx context.Context,
+	namespace string,
+	keep []domain.ManifestObject,
+) (int, error) {
+	existing, err := s.clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	var previous []domain.ManifestObject
+	if inventory, ok := existing.Annotations[ManifestsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(inventory), &previous); err != nil {
+			return 0, fmt.Errorf("failed to decode manifest inventory: %w", err)
+		}
+	}
+
+	pruned := 0
+	for _, object := range previous {
+		if slices.Contains(keep, object) {
+			continue
+		}
+
+		deleted, err := s.deleteObject(ctx, namespace, object)
+		if err != nil {
+			return pruned, err
+		}
+		if deleted {
+			pruned++
+		}
+	}
+
+	if keep == nil {
+		keep = []domain.ManifestObject{}
+	}
+
+	inventory, err := json.Marshal(keep)
+	if err != nil {
+		return pruned, fmt.Errorf("failed to encode manifest inventory: %w", err)
+	}
+
+	if existing.Annotations[ManifestsAnnotation] == string(inventory) {
+		return pruned, nil
+	}
+
+	err = applyNamespaceMetadata(
+		ctx,
+		s.clientset,
+		namespace,
+		ManifestsFieldManager,
+		nil,
+		map[string]string{ManifestsAnnotation: string(inventory)},
+	)
+	if err != nil {
+		return pruned, fmt.Errorf("failed to update manifest inventory: %w", err)
+	}
+
+	return pruned, nil
+}
+
+func (s *KubernetesManifestService) deleteObject(
+	ctx context.Context,
+	namespace string,
+	object domain.ManifestObject,
+) (bool, error) {
+	resourceClient, err := s.resourceClient(
+		namespace,
+		schema.FromAPIVersionAndKind(object.APIVersion, object.Kind),
+	)
+	if meta.IsNoMatchError(err) {
+		// 🔹 The resource type does not exist anymore, neither do its objects
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	existing, err := resourceClient.Get(ctx, object.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s %s: %w", object.Kind, object.Name, err)
+	}
+
+	if ignore, ok := existing.GetAnnotations()[IgnoreAnnotation]; ok && ignore == "true" {
+		return false, nil
+	}
+
+	if existing.GetLabels()[ManagedByLabel] != ManagedByValue {
+		return false, nil
+	}
+
+	err = resourceClient.Delete(ctx, object.Name, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+	if err != nil && !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete %s %s: %w", object.Kind, object.Name, err)
+	}
+
+	return true, nil
+}
+
+func (s *KubernetesManifestService) resourceClient(
+	namespace string,
+	gvk schema.GroupVersionKind,
+) (dynamic.ResourceInterface, error) {
+	mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resource for %s: %w", gvk, err)
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return nil, fmt.Errorf("%s is cluster-scoped, only namespaced objects are supported", gvk)
+	}
+
+	return s.dynamicClient.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+func manifestLabels() map[string]string {
+	return labels.Merge(managedLabels(), map[string]string{ManagedByLabel: ManagedByValue})
+}
+
+// decodeManifest splits a rendered manifest into its YAML documents. Empty documents, typically
+// produced by conditional templates, are skipped.
+func decodeManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(manifest)), 4096)
+
+	var objects []*unstructured.Unstructured
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+
+		if object.GetName() == "" {
+			return nil, fmt.Errorf("%s without metadata.name", object.GetKind())
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+// isSubset reports whether every field set in desired has the same value in existing.
+func isSubset(desired, existing interface{}) bool {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		existingValue, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range desiredValue {
+			if !isSubset(value, existingValue[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		existingValue, ok := existing.([]interface{})
+		if !ok || len(existingValue) != len(desiredValue) {
+			return false
+		}
+		for i := range desiredValue {
+			if !isSubset(desiredValue[i], existingValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(desired, existing)
+	}
+}
@@ -0,0 +1,260 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const caBundleManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ca-bundle
+data:
+  ca.crt: my-certificate
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: onyxia
+`
+
+var configMapResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+func setupManifestService(
+	namespace *v1.Namespace,
+	objects ...runtime.Object,
+) (*KubernetesManifestService, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(
+		schema.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"},
+		meta.RESTScopeNamespace,
+	)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	clientset := fake.NewClientset(namespace)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+
+	return NewKubernetesManifestService(clientset, dynamicClient, mapper), clientset, dynamicClient
+}
+
+func testNamespace(annotations map[string]string) *v1.Namespace {
+	return &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Annotations: annotations},
+	}
+}
+
+func existingConfigMap(name string, data map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "test-namespace",
+			"labels":    map[string]interface{}{},
+		},
+		"data": data,
+	}}
+	object.SetLabels(manifestLabels())
+	return object
+}
+
+// ✅ Test: Create every object of a manifest
+func TestApplyManifest_Created(t *testing.T) {
+	service, _, dynamicClient := setupManifestService(testNamespace(nil))
+
+	applied, err := service.ApplyManifest(context.Background(), "test-namespace", caBundleManifest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interfaces.AppliedManifestObject{
+		{
+			Object: domain.ManifestObject{APIVersion: "v1", Kind: "ConfigMap", Name: "ca-bundle"},
+			Result: interfaces.ManifestCreated,
+		},
+		{
+			Object: domain.ManifestObject{APIVersion: "v1", Kind: "ServiceAccount", Name: "onyxia"},
+			Result: interfaces.ManifestCreated,
+		},
+	}, applied)
+
+	configMap, err := dynamicClient.Resource(configMapResource).
+		Namespace("test-namespace").
+		Get(context.Background(), "ca-bundle", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, ManagedByValue, configMap.GetLabels()[ManagedByLabel])
+	assert.Equal(t, "onyxia", configMap.GetLabels()["created-by"])
+}
+
+// ✅ Test: Object Already Exists with Unchanged Values
+func TestApplyManifest_Unchanged(t *testing.T) {
+	existing := existingConfigMap("ca-bundle", map[string]interface{}{"ca.crt": "my-certificate"})
+	existing.SetResourceVersion("42")
+
+	service, _, _ := setupManifestService(testNamespace(nil), existing)
+
+	applied, err := service.ApplyManifest(context.Background(), "test-namespace", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ca-bundle
+data:
+  ca.crt: my-certificate
+`)
+
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, interfaces.ManifestUnchanged, applied[0].Result)
+}
+
+// ✅ Test: Object Updated
+func TestApplyManifest_Updated(t *testing.T) {
+	existing := existingConfigMap("ca-bundle", map[string]interface{}{"ca.crt": "old-certificate"})
+
+	service, _, dynamicClient := setupManifestService(testNamespace(nil), existing)
+
+	applied, err := service.ApplyManifest(context.Background(), "test-namespace", caBundleManifest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.ManifestUpdated, applied[0].Result)
+
+	configMap, err := dynamicClient.Resource(configMapResource).
+		Namespace("test-namespace").
+		Get(context.Background(), "ca-bundle", metav1.GetOptions{})
+	assert.NoError(t, err)
+	data, _, _ := unstructured.NestedStringMap(configMap.Object, "data")
+	assert.Equal(t, "my-certificate", data["ca.crt"])
+}
+
+// ✅ Test: Object is Ignored Due to Annotation
+func TestApplyManifest_Ignored(t *testing.T) {
+	existing := existingConfigMap("ca-bundle", map[string]interface{}{"ca.crt": "old-certificate"})
+	existing.SetAnnotations(map[string]string{IgnoreAnnotation: "true"})
+
+	service, _, _ := setupManifestService(testNamespace(nil), existing)
+
+	applied, err := service.ApplyManifest(context.Background(), "test-namespace", caBundleManifest)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.ManifestIgnored, applied[0].Result)
+}
+
+// ❌ Test: Cluster-scoped objects are rejected
+func TestApplyManifest_ClusterScoped(t *testing.T) {
+	service, _, _ := setupManifestService(testNamespace(nil))
+
+	_, err := service.ApplyManifest(context.Background(), "test-namespace", `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: another-namespace
+`)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cluster-scoped")
+}
+
+// ❌ Test: Invalid Manifest
+func TestApplyManifest_InvalidManifest(t *testing.T) {
+	service, _, _ := setupManifestService(testNamespace(nil))
+
+	_, err := service.ApplyManifest(context.Background(), "test-namespace", `
+apiVersion: v1
+metadata:
+  name: no-kind
+`)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error decoding manifest")
+}
+
+// ❌ Test: Failure When Creating an Object
+func TestApplyManifest_FailureCreate(t *testing.T) {
+	service, _, dynamicClient := setupManifestService(testNamespace(nil))
+
+	dynamicClient.PrependReactor("create", "configmaps",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to create configmap")
+		})
+
+	_, err := service.ApplyManifest(context.Background(), "test-namespace", caBundleManifest)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create ConfigMap ca-bundle")
+}
+
+// ✅ Test: Prune objects of removed templates and record the new inventory
+func TestPruneManifests(t *testing.T) {
+	removed := existingConfigMap("removed", nil)
+	ignored := existingConfigMap("ignored", nil)
+	ignored.SetAnnotations(map[string]string{IgnoreAnnotation: "true"})
+	unmanaged := existingConfigMap("unmanaged", nil)
+	unmanaged.SetLabels(nil)
+	kept := existingConfigMap("kept", nil)
+
+	service, clientset, dynamicClient := setupManifestService(
+		testNamespace(map[string]string{
+			ManifestsAnnotation: `[
+				{"apiVersion":"v1","kind":"ConfigMap","name":"removed"},
+				{"apiVersion":"v1","kind":"ConfigMap","name":"ignored"},
+				{"apiVersion":"v1","kind":"ConfigMap","name":"unmanaged"},
+				{"apiVersion":"v1","kind":"ConfigMap","name":"kept"},
+				{"apiVersion":"v1","kind":"ConfigMap","name":"already-deleted"},
+				{"apiVersion":"example.com/v1","kind":"Removed","name":"unknown-kind"}
+			]`,
+		}),
+		removed, ignored, unmanaged, kept,
+	)
+
+	keep := []domain.ManifestObject{{APIVersion: "v1", Kind: "ConfigMap", Name: "kept"}}
+
+	count, err := service.PruneManifests(context.Background(), "test-namespace", keep)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	configMaps := dynamicClient.Resource(configMapResource).Namespace("test-namespace")
+
+	_, err = configMaps.Get(context.Background(), "removed", metav1.GetOptions{})
+	assert.Error(t, err, "Expected object of a removed template to be deleted")
+
+	for _, name := range []string{"ignored", "unmanaged", "kept"} {
+		_, err = configMaps.Get(context.Background(), name, metav1.GetOptions{})
+		assert.NoError(t, err)
+	}
+
+	namespace, err := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "test-namespace", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		`[{"apiVersion":"v1","kind":"ConfigMap","name":"kept"}]`,
+		namespace.Annotations[ManifestsAnnotation],
+	)
+}
+
+// ❌ Test: Failure When Getting the Namespace
+func TestPruneManifests_NamespaceNotFound(t *testing.T) {
+	service, _, _ := setupManifestService(testNamespace(nil))
+
+	count, err := service.PruneManifests(context.Background(), "unknown-namespace", nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, count)
+	assert.Contains(t, err.Error(), "failed to get namespace")
+}
@@ -0,0 +1,145 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const LimitRangeName string = "onyxia-limit-range"
+
+func (s *KubernetesNamespaceService) ApplyLimitRange(
+	ctx context.Context,
+	namespace string,
+	limitRange *domain.LimitRange,
+) (interfaces.LimitRangeApplicationResult, error) {
+	limitRangesClient := s.clientset.CoreV1().LimitRanges(namespace)
+
+	item, err := convertLimitRangeToItem(*limitRange)
+	if err != nil {
+		return "", fmt.Errorf("error converting limit range to LimitRange: %w", err)
+	}
+
+	desired := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      LimitRangeName,
+			Namespace: namespace,
+			Labels:    managedLabels(),
+		},
+		Spec: v1.LimitRangeSpec{
+			Limits: []v1.LimitRangeItem{item},
+		},
+	}
+
+	existing, err := limitRangesClient.Get(ctx, LimitRangeName, metav1.GetOptions{})
+
+	if errors.IsNotFound(err) {
+		_, err = limitRangesClient.Create(ctx, desired, createOptions(ctx))
+		if err != nil {
+			return "", fmt.Errorf("failed to create limit range: %w", err)
+		}
+		return interfaces.LimitRangeCreated, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unexpected error checking for existing limit range: %w", err)
+	}
+
+	if ignore, ok := existing.Annotations[IgnoreAnnotation]; ok && ignore == "true" {
+		return interfaces.LimitRangeIgnored, nil
+	}
+
+	if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return interfaces.LimitRangeUnchanged, nil
+	}
+
+	existing.Spec = desired.Spec
+	_, err = limitRangesClient.Update(ctx, existing, updateOptions(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to update limit range: %w", err)
+	}
+
+	return interfaces.LimitRangeUpdated, nil
+}
+
+// DeleteLimitRange removes the LimitRange left by a profile that no longer configures one.
+func (s *KubernetesNamespaceService) DeleteLimitRange(
+	ctx context.Context,
+	namespace string,
+) (interfaces.LimitRangeApplicationResult, error) {
+	limitRangesClient := s.clientset.CoreV1().LimitRanges(namespace)
+
+	existing, err := limitRangesClient.Get(ctx, LimitRangeName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return interfaces.LimitRangeUnchanged, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unexpected error checking for existing limit range: %w", err)
+	}
+
+	if existing.Annotations[IgnoreAnnotation] == "true" {
+		return interfaces.LimitRangeIgnored, nil
+	}
+
+	// 🔹 A limit range of the same name created by someone else is not ours to delete
+	if !isManaged(existing.Labels) {
+		slog.WarnContext(ctx, "⚠️ Limit range is not managed by the onboarding, keeping it",
+			slog.String("namespace", namespace),
+			slog.String("limitRange", LimitRangeName),
+		)
+		return interfaces.LimitRangeUnchanged, nil
+	}
+
+	err = limitRangesClient.Delete(ctx, LimitRangeName, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+	if err != nil && !errors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to delete limit range: %w", err)
+	}
+
+	return interfaces.LimitRangeDeleted, nil
+}
+
+// convertLimitRangeToItem builds a container LimitRangeItem, defaulted the same way as the API
+// server does so that an unchanged LimitRange is not updated on every onboarding.
+func convertLimitRangeToItem(limitRange domain.LimitRange) (v1.LimitRangeItem, error) {
+	item := v1.LimitRangeItem{Type: v1.LimitTypeContainer}
+
+	var err error
+	if item.Default, err = parseResourceList(map[v1.ResourceName]string{
+		v1.ResourceCPU:    limitRange.DefaultCPU,
+		v1.ResourceMemory: limitRange.DefaultMemory,
+	}); err != nil {
+		return item, err
+	}
+	if item.DefaultRequest, err = parseResourceList(map[v1.ResourceName]string{
+		v1.ResourceCPU:    limitRange.DefaultRequestCPU,
+		v1.ResourceMemory: limitRange.DefaultRequestMemory,
+	}); err != nil {
+		return item, err
+	}
+	if item.Max, err = parseResourceList(map[v1.ResourceName]string{
+		v1.ResourceCPU:    limitRange.MaxCPU,
+		v1.ResourceMemory: limitRange.MaxMemory,
+	}); err != nil {
+		return item, err
+	}
+
+	for name, value := range item.Max {
+		if _, exists := item.Default[name]; !exists {
+			item.Default[name] = value.DeepCopy()
+		}
+	}
+	for name, value := range item.Default {
+		if _, exists := item.DefaultRequest[name]; !exists {
+			item.DefaultRequest[name] = value.DeepCopy()
+		}
+	}
+
+	return item, nil
+}
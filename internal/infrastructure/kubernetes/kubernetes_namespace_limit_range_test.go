@@ -0,0 +1,215 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func existingLimitRange(t *testing.T, limitRange domain.LimitRange) *v1.LimitRange {
+	item, err := convertLimitRangeToItem(limitRange)
+	assert.NoError(t, err)
+
+	return &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: LimitRangeName, Namespace: "test-namespace"},
+		Spec:       v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{item}},
+	}
+}
+
+// ✅ Test: Create LimitRange Successfully
+func TestApplyLimitRange_Created(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(
+		context.Background(),
+		"test-namespace",
+		&domain.LimitRange{DefaultCPU: "1", DefaultMemory: "2Gi", MaxCPU: "4"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.LimitRangeCreated, result)
+
+	limitRange, err := clientset.CoreV1().
+		LimitRanges("test-namespace").
+		Get(context.Background(), LimitRangeName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "onyxia", limitRange.Labels["created-by"])
+
+	item := limitRange.Spec.Limits[0]
+	assert.Equal(t, v1.LimitTypeContainer, item.Type)
+	assert.True(t, item.Default.Cpu().Equal(resource.MustParse("1")))
+	// ✅ Default requests fall back to default limits, like the API server does
+	assert.True(t, item.DefaultRequest.Memory().Equal(resource.MustParse("2Gi")))
+	assert.True(t, item.Max.Cpu().Equal(resource.MustParse("4")))
+}
+
+// ✅ Test: LimitRange Already Exists with Unchanged Values
+func TestApplyLimitRange_Unchanged(t *testing.T) {
+	limitRange := domain.LimitRange{DefaultCPU: "1", MaxMemory: "8Gi"}
+	clientset := fake.NewSimpleClientset(existingLimitRange(t, limitRange))
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(context.Background(), "test-namespace", &limitRange)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.LimitRangeUnchanged, result)
+}
+
+// ✅ Test: LimitRange Updated
+func TestApplyLimitRange_Updated(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		existingLimitRange(t, domain.LimitRange{DefaultCPU: "1"}),
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(
+		context.Background(),
+		"test-namespace",
+		&domain.LimitRange{DefaultCPU: "2"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.LimitRangeUpdated, result)
+
+	limitRange, err := clientset.CoreV1().
+		LimitRanges("test-namespace").
+		Get(context.Background(), LimitRangeName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, limitRange.Spec.Limits[0].Default.Cpu().Equal(resource.MustParse("2")))
+}
+
+// ✅ Test: LimitRange is Ignored Due to Annotation
+func TestApplyLimitRange_Ignored(t *testing.T) {
+	existing := existingLimitRange(t, domain.LimitRange{DefaultCPU: "1"})
+	existing.Annotations = map[string]string{IgnoreAnnotation: "true"}
+
+	clientset := fake.NewSimpleClientset(existing)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(
+		context.Background(),
+		"test-namespace",
+		&domain.LimitRange{DefaultCPU: "2"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.LimitRangeIgnored, result)
+}
+
+// ❌ Test: Invalid Quantity
+func TestApplyLimitRange_FailureConvert(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyLimitRange(
+		context.Background(),
+		"test-namespace",
+		&domain.LimitRange{MaxMemory: "invalid"},
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.LimitRangeApplicationResult(""), result)
+	assert.Contains(t, err.Error(), "error converting limit range")
+}
+
+// ❌ Test: Failure When Creating a LimitRange
+func TestApplyLimitRange_FailureCreate(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor("create", "limitranges",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to create limit range")
+		})
+
+	result, err := service.ApplyLimitRange(
+		context.Background(),
+		"test-namespace",
+		&domain.LimitRange{DefaultCPU: "1"},
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.LimitRangeApplicationResult(""), result)
+	assert.Contains(t, err.Error(), "failed to create limit range")
+}
+
+// ✅ Test: Delete Managed LimitRange
+func TestDeleteLimitRange(t *testing.T) {
+	existing := existingLimitRange(t, domain.LimitRange{DefaultCPU: "1"})
+	existing.Labels = managedLabels()
+	clientset := fake.NewSimpleClientset(existing)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.DeleteLimitRange(context.Background(), "test-namespace")
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.LimitRangeDeleted, result)
+
+	limitRanges, _ := clientset.CoreV1().
+		LimitRanges("test-namespace").
+		List(context.Background(), metav1.ListOptions{})
+	assert.Empty(t, limitRanges.Items)
+}
+
+// ✅ Test: LimitRange Kept When Ignored or Not Managed
+func TestDeleteLimitRange_Kept(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		expected    interfaces.LimitRangeApplicationResult
+	}{
+		{
+			name:        "ignored",
+			labels:      managedLabels(),
+			annotations: map[string]string{IgnoreAnnotation: "true"},
+			expected:    interfaces.LimitRangeIgnored,
+		},
+		{
+			name:     "not managed",
+			labels:   map[string]string{"created-by": "admin"},
+			expected: interfaces.LimitRangeUnchanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := existingLimitRange(t, domain.LimitRange{DefaultCPU: "1"})
+			existing.Labels = tt.labels
+			existing.Annotations = tt.annotations
+			clientset := fake.NewSimpleClientset(existing)
+			service := NewKubernetesNamespaceService(clientset)
+
+			result, err := service.DeleteLimitRange(context.Background(), "test-namespace")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+
+			_, err = clientset.CoreV1().
+				LimitRanges("test-namespace").
+				Get(context.Background(), LimitRangeName, metav1.GetOptions{})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// ✅ Test: No LimitRange to Delete
+func TestDeleteLimitRange_NotFound(t *testing.T) {
+	service := NewKubernetesNamespaceService(fake.NewSimpleClientset())
+
+	result, err := service.DeleteLimitRange(context.Background(), "test-namespace")
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.LimitRangeUnchanged, result)
+}
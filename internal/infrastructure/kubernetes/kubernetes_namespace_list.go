@@ -0,0 +1,51 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListNamespaces returns the namespaces owned by the onboarding matching all the labels of
+// selector.
+func (s *KubernetesNamespaceService) ListNamespaces(
+	ctx context.Context,
+	selector map[string]string,
+) ([]domain.ManagedNamespace, error) {
+	namespaces, err := s.listNamespaces(ctx, labels.Merge(selector, managedLabels()).AsSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ManagedNamespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		result = append(result, domain.ManagedNamespace{
+			Name:        namespace.Name,
+			Annotations: namespace.Annotations,
+			Archived:    namespace.Labels[ArchivedLabel] == "true",
+		})
+	}
+
+	return result, nil
+}
+
+func (s *KubernetesNamespaceService) listNamespaces(
+	ctx context.Context,
+	selector labels.Selector,
+) ([]v1.Namespace, error) {
+	if s.cache.HasSynced() {
+		return s.cache.listNamespaces(selector), nil
+	}
+
+	namespaces, err := s.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	return namespaces.Items, nil
+}
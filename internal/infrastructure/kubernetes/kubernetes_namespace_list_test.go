@@ -0,0 +1,52 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// ✅ Test: Only Namespaces with the Onboarding Labels are Listed
+func TestListNamespaces(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-jdoe",
+			Labels:      map[string]string{"created-by": "onyxia", "team": "data"},
+			Annotations: map[string]string{domain.IdentityAnnotation: "user:jdoe"},
+		}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "user-left",
+			Labels: map[string]string{
+				"created-by":  "onyxia",
+				"team":        "data",
+				ArchivedLabel: "true",
+			},
+		}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		// 🔹 Carries the configured labels but was not created by onboarding
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "monitoring",
+			Labels: map[string]string{"team": "data"},
+		}},
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	namespaces, err := service.ListNamespaces(
+		context.Background(),
+		map[string]string{"team": "data"},
+	)
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []domain.ManagedNamespace{
+		{
+			Name:        "user-jdoe",
+			Annotations: map[string]string{domain.IdentityAnnotation: "user:jdoe"},
+		},
+		{Name: "user-left", Archived: true},
+	}, namespaces)
+}
@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+// NetworkPolicyLabel marks the NetworkPolicies applied from the networkPolicies configuration, the
+// only ones pruned once removed from it: NetworkPolicies applied from manifests also carry the
+// managed labels.
+const NetworkPolicyLabel string = "onyxia.sh/network-policy"
+const NetworkPolicyLabelValue string = "config"
+
+func (s *KubernetesNamespaceService) ApplyNetworkPolicy(
+	ctx context.Context,
+	namespace string,
+	policy *domain.NetworkPolicy,
+) (interfaces.NetworkPolicyApplicationResult, error) {
+	policiesClient := s.clientset.NetworkingV1().NetworkPolicies(namespace)
+
+	spec, err := ParseNetworkPolicySpec(policy.Spec)
+	if err != nil {
+		return "", fmt.Errorf("error converting network policy %q: %w", policy.Name, err)
+	}
+
+	desired := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policy.Name,
+			Namespace: namespace,
+			Labels:    networkPolicyLabels(),
+		},
+		Spec: *spec,
+	}
+
+	existing, err := policiesClient.Get(ctx, policy.Name, metav1.GetOptions{})
+
+	if errors.IsNotFound(err) {
+		_, err = policiesClient.Create(ctx, desired, createOptions(ctx))
+		if err != nil {
+			return "", fmt.Errorf("failed to create network policy: %w", err)
+		}
+		return interfaces.NetworkPolicyCreated, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unexpected error checking for existing network policy: %w", err)
+	}
+
+	if ignore, ok := existing.Annotations[IgnoreAnnotation]; ok && ignore == "true" {
+		return interfaces.NetworkPolicyIgnored, nil
+	}
+
+	// 🔹 Policies applied before NetworkPolicyLabel existed are labelled, so that they get pruned
+	labelled := existing.Labels[NetworkPolicyLabel] == NetworkPolicyLabelValue
+	if labelled && equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return interfaces.NetworkPolicyUnchanged, nil
+	}
+
+	existing.Labels = labels.Merge(existing.Labels, desired.Labels)
+	existing.Spec = desired.Spec
+	_, err = policiesClient.Update(ctx, existing, updateOptions(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to update network policy: %w", err)
+	}
+
+	return interfaces.NetworkPolicyUpdated, nil
+}
+
+// PruneNetworkPolicies deletes the NetworkPolicies applied from the configuration to a namespace
+// whose name is not in keep, e.g. removed from the configuration. NetworkPolicies marked as
+// ignored, and those applied from manifests, are kept.
+func (s *KubernetesNamespaceService) PruneNetworkPolicies(
+	ctx context.Context,
+	namespace string,
+	keep []string,
+) (int, error) {
+	policiesClient := s.clientset.NetworkingV1().NetworkPolicies(namespace)
+
+	policies, err := policiesClient.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(networkPolicyLabels()).String(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list network policies: %w", err)
+	}
+
+	pruned := 0
+	for _, policy := range policies.Items {
+		if slices.Contains(keep, policy.Name) {
+			continue
+		}
+
+		if ignore, ok := policy.Annotations[IgnoreAnnotation]; ok && ignore == "true" {
+			continue
+		}
+
+		err := policiesClient.Delete(ctx, policy.Name, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+		if err != nil && !errors.IsNotFound(err) {
+			return pruned, fmt.Errorf("failed to delete network policy %s: %w", policy.Name, err)
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+func networkPolicyLabels() map[string]string {
+	return labels.Merge(managedLabels(), map[string]string{
+		NetworkPolicyLabel: NetworkPolicyLabelValue,
+	})
+}
+
+// ParseNetworkPolicySpec decodes a YAML NetworkPolicySpec and applies the same policyTypes
+// defaulting as the API server, so that unchanged policies are detected as such.
+func ParseNetworkPolicySpec(spec string) (*networkingv1.NetworkPolicySpec, error) {
+	var result networkingv1.NetworkPolicySpec
+
+	if err := yaml.UnmarshalStrict([]byte(spec), &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.PolicyTypes) == 0 {
+		result.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		if len(result.Egress) > 0 {
+			result.PolicyTypes = append(result.PolicyTypes, networkingv1.PolicyTypeEgress)
+		}
+	}
+
+	return &result, nil
+}
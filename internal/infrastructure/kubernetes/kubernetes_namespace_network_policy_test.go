@@ -0,0 +1,243 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const denyAllIngressSpec = `
+podSelector: {}
+policyTypes:
+  - Ingress
+`
+
+const allowSameNamespaceSpec = `
+podSelector: {}
+ingress:
+  - from:
+      - podSelector: {}
+`
+
+func existingNetworkPolicy(t *testing.T, spec string) *networkingv1.NetworkPolicy {
+	parsed, err := ParseNetworkPolicySpec(spec)
+	assert.NoError(t, err)
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-policy",
+			Namespace: "test-namespace",
+			Labels:    networkPolicyLabels(),
+		},
+		Spec: *parsed,
+	}
+}
+
+// ✅ Test: Create NetworkPolicy Successfully
+func TestApplyNetworkPolicy_Created(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(
+		context.Background(),
+		"test-namespace",
+		&domain.NetworkPolicy{Name: "test-policy", Spec: denyAllIngressSpec},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NetworkPolicyCreated, result)
+
+	policy, err := clientset.NetworkingV1().
+		NetworkPolicies("test-namespace").
+		Get(context.Background(), "test-policy", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "onyxia", policy.Labels["created-by"])
+	assert.Equal(t, NetworkPolicyLabelValue, policy.Labels[NetworkPolicyLabel])
+	assert.Equal(
+		t,
+		[]networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		policy.Spec.PolicyTypes,
+	)
+}
+
+// ✅ Test: NetworkPolicy Already Exists with Unchanged Spec
+func TestApplyNetworkPolicy_Unchanged(t *testing.T) {
+	clientset := fake.NewSimpleClientset(existingNetworkPolicy(t, allowSameNamespaceSpec))
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(
+		context.Background(),
+		"test-namespace",
+		&domain.NetworkPolicy{Name: "test-policy", Spec: allowSameNamespaceSpec},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NetworkPolicyUnchanged, result)
+}
+
+// ✅ Test: NetworkPolicy Updated
+func TestApplyNetworkPolicy_Updated(t *testing.T) {
+	clientset := fake.NewSimpleClientset(existingNetworkPolicy(t, allowSameNamespaceSpec))
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(
+		context.Background(),
+		"test-namespace",
+		&domain.NetworkPolicy{Name: "test-policy", Spec: denyAllIngressSpec},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NetworkPolicyUpdated, result)
+
+	policy, err := clientset.NetworkingV1().
+		NetworkPolicies("test-namespace").
+		Get(context.Background(), "test-policy", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, policy.Spec.Ingress)
+}
+
+// ✅ Test: NetworkPolicy Applied Before the Configuration Label is Labelled
+func TestApplyNetworkPolicy_Labelled(t *testing.T) {
+	existing := existingNetworkPolicy(t, allowSameNamespaceSpec)
+	existing.Labels = managedLabels()
+
+	clientset := fake.NewSimpleClientset(existing)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(
+		context.Background(),
+		"test-namespace",
+		&domain.NetworkPolicy{Name: "test-policy", Spec: allowSameNamespaceSpec},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NetworkPolicyUpdated, result)
+
+	policy, err := clientset.NetworkingV1().
+		NetworkPolicies("test-namespace").
+		Get(context.Background(), "test-policy", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, NetworkPolicyLabelValue, policy.Labels[NetworkPolicyLabel])
+}
+
+// ✅ Test: NetworkPolicy is Ignored Due to Annotation
+func TestApplyNetworkPolicy_Ignored(t *testing.T) {
+	existing := existingNetworkPolicy(t, allowSameNamespaceSpec)
+	existing.Annotations = map[string]string{IgnoreAnnotation: "true"}
+
+	clientset := fake.NewSimpleClientset(existing)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(
+		context.Background(),
+		"test-namespace",
+		&domain.NetworkPolicy{Name: "test-policy", Spec: denyAllIngressSpec},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NetworkPolicyIgnored, result)
+}
+
+// ❌ Test: Invalid NetworkPolicy Spec
+func TestApplyNetworkPolicy_InvalidSpec(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyNetworkPolicy(
+		context.Background(),
+		"test-namespace",
+		&domain.NetworkPolicy{Name: "test-policy", Spec: "podSelectr: {}"},
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.NetworkPolicyApplicationResult(""), result)
+	assert.Contains(t, err.Error(), "error converting network policy")
+}
+
+// ❌ Test: Failure When Updating a NetworkPolicy
+func TestApplyNetworkPolicy_FailureUpdate(t *testing.T) {
+	clientset := fake.NewSimpleClientset(existingNetworkPolicy(t, allowSameNamespaceSpec))
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor("update", "networkpolicies",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to update network policy")
+		})
+
+	result, err := service.ApplyNetworkPolicy(
+		context.Background(),
+		"test-namespace",
+		&domain.NetworkPolicy{Name: "test-policy", Spec: denyAllIngressSpec},
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.NetworkPolicyApplicationResult(""), result)
+	assert.Contains(t, err.Error(), "failed to update network policy")
+}
+
+// ✅ Test: Prune Managed NetworkPolicies No Longer Configured
+func TestPruneNetworkPolicies(t *testing.T) {
+	policy := func(name string, labels, annotations map[string]string) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "test-namespace",
+			Labels:      labels,
+			Annotations: annotations,
+		}}
+	}
+	clientset := fake.NewClientset(
+		policy("configured", networkPolicyLabels(), nil),
+		policy("removed", networkPolicyLabels(), nil),
+		policy("ignored", networkPolicyLabels(), map[string]string{IgnoreAnnotation: "true"}),
+		policy("from-manifest", manifestLabels(), nil),
+		policy("created-by-someone-else", nil, nil),
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	pruned, err := service.PruneNetworkPolicies(
+		context.Background(),
+		"test-namespace",
+		[]string{"configured"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	policies, _ := clientset.NetworkingV1().
+		NetworkPolicies("test-namespace").
+		List(context.Background(), metav1.ListOptions{})
+	var names []string
+	for _, policy := range policies.Items {
+		names = append(names, policy.Name)
+	}
+	assert.ElementsMatch(
+		t,
+		[]string{"configured", "ignored", "from-manifest", "created-by-someone-else"},
+		names,
+	)
+}
+
+func TestParseNetworkPolicySpec_DefaultsEgressPolicyType(t *testing.T) {
+	spec, err := ParseNetworkPolicySpec(`
+podSelector: {}
+egress:
+  - to:
+      - podSelector: {}
+`)
+
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		spec.PolicyTypes,
+	)
+}
@@ -0,0 +1,201 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+const ArchivedLabel string = "onyxia.sh/archived"
+const PurgeAfterAnnotation string = "onyxia.sh/purge-after"
+
+// archivedQuotaResources are set to zero when an archived namespace has no managed quota yet.
+var archivedQuotaResources = []v1.ResourceName{
+	v1.ResourceRequestsCPU,
+	v1.ResourceRequestsMemory,
+	v1.ResourceRequestsStorage,
+	v1.ResourceName("count/pods"),
+}
+
+// OffboardNamespace deletes or archives a namespace owned by the onboarding, see checkOwnership.
+// An empty identity only accepts namespaces carrying the managed labels.
+func (s *KubernetesNamespaceService) OffboardNamespace(
+	ctx context.Context,
+	name string,
+	identity string,
+	mode domain.OffboardingMode,
+	gracePeriod time.Duration,
+) (interfaces.NamespaceOffboardingResult, error) {
+	if mode != domain.OffboardingModeDelete && mode != domain.OffboardingModeArchive {
+		return "", fmt.Errorf("unknown offboarding mode: %q", mode)
+	}
+
+	existing, err := s.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return interfaces.NamespaceNotFound, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	owned, err := checkOwnership(existing, identity)
+	if err != nil {
+		return "", err
+	}
+	if !owned {
+		return "", fmt.Errorf(
+			"%w: namespace %s was not created by onboarding",
+			domain.ErrNamespaceNotOwned,
+			name,
+		)
+	}
+
+	if mode == domain.OffboardingModeDelete {
+		return s.deleteNamespace(ctx, existing)
+	}
+	return s.archiveNamespace(ctx, existing, gracePeriod)
+}
+
+// deleteNamespace deletes the namespace checked by the caller, not one created since.
+func (s *KubernetesNamespaceService) deleteNamespace(
+	ctx context.Context,
+	namespace *v1.Namespace,
+) (interfaces.NamespaceOffboardingResult, error) {
+	err := s.clientset.CoreV1().Namespaces().Delete(ctx, namespace.Name, metav1.DeleteOptions{
+		Preconditions: metav1.NewUIDPreconditions(string(namespace.UID)),
+	})
+
+	if errors.IsNotFound(err) {
+		return interfaces.NamespaceNotFound, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to delete namespace: %w", err)
+	}
+
+	return interfaces.NamespaceDeleted, nil
+}
+
+func (s *KubernetesNamespaceService) archiveNamespace(
+	ctx context.Context,
+	namespace *v1.Namespace,
+	gracePeriod time.Duration,
+) (interfaces.NamespaceOffboardingResult, error) {
+	// 🔹 Already archived: delete it once the grace period is over, otherwise keep the data
+	if namespace.Labels[ArchivedLabel] == "true" {
+		purgeAfter, err := time.Parse(time.RFC3339, namespace.Annotations[PurgeAfterAnnotation])
+		if err == nil && time.Now().After(purgeAfter) {
+			return s.deleteNamespace(ctx, namespace)
+		}
+		return interfaces.NamespaceAlreadyArchived, nil
+	}
+
+	now := time.Now().UTC()
+
+	err := applyNamespaceMetadata(
+		ctx,
+		s.clientset,
+		namespace.Name,
+		OffboardingFieldManager,
+		map[string]string{ArchivedLabel: "true"},
+		map[string]string{
+			domain.ArchivedAtAnnotation: now.Format(time.RFC3339),
+			PurgeAfterAnnotation:        now.Add(gracePeriod).Format(time.RFC3339),
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to archive namespace: %w", err)
+	}
+
+	if err := s.zeroResourceQuota(ctx, namespace.Name); err != nil {
+		return "", err
+	}
+
+	return interfaces.NamespaceArchived, nil
+}
+
+// restoreNamespace removes the archive label and annotations of a namespace, along with the quota
+// zeroed by the archive, which the onboarding applies again.
+func (s *KubernetesNamespaceService) restoreNamespace(ctx context.Context, name string) error {
+	err := applyNamespaceMetadata(ctx, s.clientset, name, OffboardingFieldManager, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to restore archived namespace: %w", err)
+	}
+
+	quotasClient := s.clientset.CoreV1().ResourceQuotas(name)
+	quota, err := quotasClient.Get(ctx, QuotaName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("unexpected error checking for existing quota: %w", err)
+	}
+	if err == nil && isZeroed(quota) {
+		err := quotasClient.Delete(ctx, QuotaName, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete archived resource quota: %w", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "✅ Restored archived namespace", slog.String("namespace", name))
+	return nil
+}
+
+// isZeroed tells whether the quota is the managed quota zeroed by an archive.
+func isZeroed(quota *v1.ResourceQuota) bool {
+	if !isManaged(quota.Labels) || quota.Annotations[IgnoreQuotaAnnotation] == "true" {
+		return false
+	}
+	for _, value := range quota.Spec.Hard {
+		if !value.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// zeroResourceQuota sets every hard limit of the managed quota to zero so that no new workload
+// can be scheduled in an archived namespace. Existing volumes are kept. The zeroed quota is
+// applied by FieldManager, so that the next onboarding applies the quota of the owner over it.
+func (s *KubernetesNamespaceService) zeroResourceQuota(
+	ctx context.Context,
+	namespace string,
+) error {
+	quotasClient := s.clientset.CoreV1().ResourceQuotas(namespace)
+
+	existingQuota, err := quotasClient.Get(ctx, QuotaName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("unexpected error checking for existing quota: %w", err)
+	}
+
+	resources := archivedQuotaResources
+	if err == nil {
+		resources = slices.Collect(maps.Keys(existingQuota.Spec.Hard))
+	}
+
+	hardLimits := make(v1.ResourceList, len(resources))
+	for _, name := range resources {
+		hardLimits[name] = resource.MustParse("0")
+	}
+
+	_, err = quotasClient.Apply(
+		ctx,
+		corev1ac.ResourceQuota(QuotaName, namespace).
+			WithLabels(managedLabels()).
+			WithSpec(corev1ac.ResourceQuotaSpec().WithHard(hardLimits)),
+		metav1.ApplyOptions{FieldManager: FieldManager, Force: true},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to zero resource quota: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,372 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// ✅ Test: Delete Namespace Successfully
+func TestOffboardNamespace_Delete(t *testing.T) {
+	clientset := fake.NewClientset(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeDelete,
+		0,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceDeleted, result)
+
+	_, err = clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "test-namespace", metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+// ✅ Test: Delete a Namespace that does not exist
+func TestOffboardNamespace_DeleteNotFound(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeDelete,
+		0,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceNotFound, result)
+}
+
+// ❌ Test: Simulated API Failure (Delete)
+func TestOffboardNamespace_DeleteFailure(t *testing.T) {
+	clientset := fake.NewClientset(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor("delete", "namespaces",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("simulated API failure")
+		})
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeDelete,
+		0,
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.NamespaceOffboardingResult(""), result)
+	assert.Contains(t, err.Error(), "simulated API failure")
+}
+
+// ✅ Test: Archive Namespace and zero its existing quota
+func TestOffboardNamespace_Archive(t *testing.T) {
+	clientset := fake.NewClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-namespace",
+			Labels: managedLabels(),
+		}},
+		&v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
+			Spec: v1.ResourceQuotaSpec{
+				Hard: map[v1.ResourceName]resource.Quantity{
+					v1.ResourceRequestsMemory: resource.MustParse("10Gi"),
+					v1.ResourceLimitsCPU:      resource.MustParse("10"),
+				},
+			},
+		},
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeArchive,
+		24*time.Hour,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceArchived, result)
+
+	namespace, err := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "test-namespace", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", namespace.Labels[ArchivedLabel])
+	assert.Contains(t, namespace.Annotations, domain.ArchivedAtAnnotation)
+
+	purgeAfter, err := time.Parse(time.RFC3339, namespace.Annotations[PurgeAfterAnnotation])
+	assert.NoError(t, err)
+	assert.True(t, purgeAfter.After(time.Now().Add(23*time.Hour)))
+
+	quota, err := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), QuotaName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, quota.Spec.Hard, 2)
+	for name, value := range quota.Spec.Hard {
+		assert.True(t, value.IsZero(), "Expected %s to be zeroed", name)
+	}
+}
+
+// ✅ Test: Archive Namespace without existing quota creates a zeroed one
+func TestOffboardNamespace_ArchiveCreatesZeroQuota(t *testing.T) {
+	clientset := fake.NewClientset(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeArchive,
+		time.Hour,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceArchived, result)
+
+	quota, err := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), QuotaName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "onyxia", quota.Labels["created-by"])
+	countPods := quota.Spec.Hard[v1.ResourceName("count/pods")]
+	assert.True(t, countPods.IsZero())
+}
+
+// ✅ Test: Archive a Namespace that is already archived and still in its grace period
+func TestOffboardNamespace_AlreadyArchived(t *testing.T) {
+	clientset := fake.NewClientset(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-namespace",
+			Labels: labelsWithOwnership(map[string]string{ArchivedLabel: "true"}),
+			Annotations: map[string]string{
+				PurgeAfterAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339),
+			},
+		},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeArchive,
+		time.Hour,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAlreadyArchived, result)
+}
+
+// ✅ Test: Archive a Namespace whose grace period is over deletes it
+func TestOffboardNamespace_ArchiveGracePeriodExpired(t *testing.T) {
+	clientset := fake.NewClientset(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-namespace",
+			Labels: labelsWithOwnership(map[string]string{ArchivedLabel: "true"}),
+			Annotations: map[string]string{
+				PurgeAfterAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
+		},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeArchive,
+		time.Hour,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceDeleted, result)
+}
+
+// ✅ Test: Archive a Namespace that does not exist
+func TestOffboardNamespace_ArchiveNotFound(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeArchive,
+		time.Hour,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceNotFound, result)
+}
+
+// ❌ Test: Simulated API Failure (Patch)
+func TestOffboardNamespace_ArchivePatchFailure(t *testing.T) {
+	clientset := fake.NewClientset(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
+	})
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor("patch", "namespaces",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to patch namespace")
+		})
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeArchive,
+		time.Hour,
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.NamespaceOffboardingResult(""), result)
+	assert.Contains(t, err.Error(), "failed to patch namespace")
+}
+
+// ❌ Test: Unknown offboarding mode
+func TestOffboardNamespace_UnknownMode(t *testing.T) {
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingMode("shred"),
+		0,
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.NamespaceOffboardingResult(""), result)
+	assert.Contains(t, err.Error(), "unknown offboarding mode")
+}
+
+// ❌ Test: Namespaces not created by onboarding, or of another identity, are left alone
+func TestOffboardNamespace_NotOwned(t *testing.T) {
+	tests := map[string]struct {
+		namespace *v1.Namespace
+		err       error
+	}{
+		"created by someone else": {
+			namespace: &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			err:       domain.ErrNamespaceNotOwned,
+		},
+		"of another identity": {
+			namespace: &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-namespace",
+				Labels:      managedLabels(),
+				Annotations: map[string]string{domain.IdentityAnnotation: "user:Test"},
+			}},
+			err: domain.ErrNamespaceCollision,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientset := fake.NewClientset(tt.namespace)
+			service := NewKubernetesNamespaceService(clientset)
+
+			for _, mode := range []domain.OffboardingMode{
+				domain.OffboardingModeDelete,
+				domain.OffboardingModeArchive,
+			} {
+				_, err := service.OffboardNamespace(
+					context.Background(),
+					"test-namespace",
+					"user:test",
+					mode,
+					time.Hour,
+				)
+				assert.ErrorIs(t, err, tt.err)
+			}
+
+			namespace, err := clientset.CoreV1().
+				Namespaces().
+				Get(context.Background(), "test-namespace", metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.NotContains(t, namespace.Labels, ArchivedLabel)
+		})
+	}
+}
+
+// ✅ Test: Namespaces onboarded before the ownership label are recognized by their identity
+func TestOffboardNamespace_OwnedByIdentity(t *testing.T) {
+	clientset := fake.NewClientset(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test-namespace",
+		Annotations: map[string]string{domain.IdentityAnnotation: "user:test"},
+	}})
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.OffboardNamespace(
+		context.Background(),
+		"test-namespace",
+		"user:test",
+		domain.OffboardingModeDelete,
+		0,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceDeleted, result)
+}
+
+// ✅ Test: Onboarding an archived namespace again removes its archive markers and zeroed quota
+func TestCreateNamespace_RestoresArchivedNamespace(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	_, err := service.CreateNamespace(ctx, "test-namespace",
+		map[string]string{domain.IdentityAnnotation: "user:test"}, nil)
+	assert.NoError(t, err)
+	_, err = service.OffboardNamespace(ctx, "test-namespace", "user:test",
+		domain.OffboardingModeArchive, time.Hour)
+	assert.NoError(t, err)
+
+	result, err := service.CreateNamespace(ctx, "test-namespace",
+		map[string]string{domain.IdentityAnnotation: "user:test"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
+
+	namespace, _ := clientset.CoreV1().Namespaces().Get(ctx, "test-namespace", metav1.GetOptions{})
+	assert.NotContains(t, namespace.Labels, ArchivedLabel)
+	assert.NotContains(t, namespace.Annotations, domain.ArchivedAtAnnotation)
+	assert.NotContains(t, namespace.Annotations, PurgeAfterAnnotation)
+	assert.Equal(t, "user:test", namespace.Annotations[domain.IdentityAnnotation])
+	assert.Equal(t, "onyxia", namespace.Labels["created-by"])
+
+	_, err = clientset.CoreV1().ResourceQuotas("test-namespace").
+		Get(ctx, QuotaName, metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "Expected the zeroed quota to be deleted")
+}
@@ -0,0 +1,184 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+func (s *KubernetesNamespaceService) ApplyRoleBinding(
+	ctx context.Context,
+	namespace string,
+	roleBinding *domain.RoleBinding,
+) (interfaces.RoleBindingApplicationResult, error) {
+	roleBindingsClient := s.clientset.RbacV1().RoleBindings(namespace)
+
+	desired := convertRoleBinding(namespace, roleBinding)
+
+	existing, err := roleBindingsClient.Get(ctx, desired.Name, metav1.GetOptions{})
+
+	if errors.IsNotFound(err) {
+		_, err = roleBindingsClient.Create(ctx, desired, createOptions(ctx))
+		if err != nil {
+			return "", fmt.Errorf("failed to create role binding: %w", err)
+		}
+		return interfaces.RoleBindingCreated, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unexpected error checking for existing role binding: %w", err)
+	}
+
+	if ignore, ok := existing.Annotations[IgnoreAnnotation]; ok && ignore == "true" {
+		return interfaces.RoleBindingIgnored, nil
+	}
+
+	if existing.RoleRef == desired.RoleRef &&
+		slices.Equal(existing.Subjects, desired.Subjects) &&
+		hasAnnotations(existing, desired.Annotations) {
+		return interfaces.RoleBindingUnchanged, nil
+	}
+
+	// 🔹 The role reference of a RoleBinding is immutable, it has to be recreated
+	if existing.RoleRef != desired.RoleRef {
+		err = roleBindingsClient.Delete(
+			ctx,
+			desired.Name,
+			metav1.DeleteOptions{DryRun: dryRun(ctx)},
+		)
+		if err != nil && !errors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to delete role binding: %w", err)
+		}
+
+		// 🔹 The deletion was not persisted, creating the RoleBinding again would conflict
+		if domain.IsDryRun(ctx) {
+			return interfaces.RoleBindingUpdated, nil
+		}
+
+		_, err = roleBindingsClient.Create(ctx, desired, createOptions(ctx))
+		if err != nil {
+			return "", fmt.Errorf("failed to recreate role binding: %w", err)
+		}
+		return interfaces.RoleBindingUpdated, nil
+	}
+
+	existing.Subjects = desired.Subjects
+	existing.Annotations = labels.Merge(existing.Annotations, desired.Annotations)
+	_, err = roleBindingsClient.Update(ctx, existing, updateOptions(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to update role binding: %w", err)
+	}
+
+	return interfaces.RoleBindingUpdated, nil
+}
+
+// PruneRoleBindings deletes the managed RoleBindings of a namespace carrying label that were last
+// seen before seenBefore, according to their domain.MemberSeenAtAnnotation, or created before it
+// when they have none. RoleBindings marked as ignored are kept.
+func (s *KubernetesNamespaceService) PruneRoleBindings(
+	ctx context.Context,
+	namespace string,
+	label string,
+	seenBefore time.Time,
+) (int, error) {
+	roleBindingsClient := s.clientset.RbacV1().RoleBindings(namespace)
+
+	labelled, err := labels.NewRequirement(label, selection.Exists, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid role binding label %q: %w", label, err)
+	}
+	selector := labels.SelectorFromSet(managedLabels()).Add(*labelled)
+
+	roleBindings, err := roleBindingsClient.List(
+		ctx,
+		metav1.ListOptions{LabelSelector: selector.String()},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list role bindings: %w", err)
+	}
+
+	pruned := 0
+	for _, roleBinding := range roleBindings.Items {
+		if ignore, ok := roleBinding.Annotations[IgnoreAnnotation]; ok && ignore == "true" {
+			continue
+		}
+
+		if !lastSeen(roleBinding).Before(seenBefore) {
+			continue
+		}
+
+		err := roleBindingsClient.Delete(
+			ctx,
+			roleBinding.Name,
+			metav1.DeleteOptions{DryRun: dryRun(ctx)},
+		)
+		if err != nil && !errors.IsNotFound(err) {
+			return pruned, fmt.Errorf(
+				"failed to delete role binding %s/%s: %w",
+				namespace,
+				roleBinding.Name,
+				err,
+			)
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// lastSeen returns when the member of a RoleBinding was last seen, or when the RoleBinding was
+// created for those applied before domain.MemberSeenAtAnnotation existed.
+func lastSeen(roleBinding rbacv1.RoleBinding) time.Time {
+	seenAt, err := time.Parse(
+		time.RFC3339,
+		roleBinding.Annotations[domain.MemberSeenAtAnnotation],
+	)
+	if err != nil {
+		return roleBinding.CreationTimestamp.Time
+	}
+	return seenAt
+}
+
+func hasAnnotations(roleBinding *rbacv1.RoleBinding, annotations map[string]string) bool {
+	for key, value := range annotations {
+		if roleBinding.Annotations[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func convertRoleBinding(namespace string, roleBinding *domain.RoleBinding) *rbacv1.RoleBinding {
+	subjects := make([]rbacv1.Subject, 0, len(roleBinding.Subjects))
+	for _, subject := range roleBinding.Subjects {
+		subjects = append(subjects, rbacv1.Subject{
+			Kind:     subject.Kind,
+			APIGroup: rbacv1.GroupName,
+			Name:     subject.Name,
+		})
+	}
+
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        roleBinding.Name,
+			Namespace:   namespace,
+			Labels:      labels.Merge(roleBinding.Labels, managedLabels()),
+			Annotations: roleBinding.Annotations,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     roleBinding.ClusterRole,
+		},
+		Subjects: subjects,
+	}
+}
@@ -0,0 +1,289 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testRoleBinding() *domain.RoleBinding {
+	return &domain.RoleBinding{
+		Name:        "onyxia-user",
+		ClusterRole: "admin",
+		Subjects: []domain.Subject{
+			{Kind: domain.SubjectKindUser, Name: "oidc:test-user"},
+		},
+	}
+}
+
+// ✅ Test: Create RoleBinding Successfully
+func TestApplyRoleBinding_Created(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(
+		context.Background(),
+		"test-namespace",
+		testRoleBinding(),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.RoleBindingCreated, result)
+
+	roleBinding, err := clientset.RbacV1().
+		RoleBindings("test-namespace").
+		Get(context.Background(), "onyxia-user", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", roleBinding.RoleRef.Name)
+	assert.Equal(t, "ClusterRole", roleBinding.RoleRef.Kind)
+	assert.Equal(t, "onyxia", roleBinding.Labels["created-by"])
+	assert.Equal(t, []rbacv1.Subject{{
+		Kind:     "User",
+		APIGroup: rbacv1.GroupName,
+		Name:     "oidc:test-user",
+	}}, roleBinding.Subjects)
+}
+
+// ✅ Test: RoleBinding Already Exists with Unchanged Values
+func TestApplyRoleBinding_Unchanged(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		convertRoleBinding("test-namespace", testRoleBinding()),
+	)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(
+		context.Background(),
+		"test-namespace",
+		testRoleBinding(),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.RoleBindingUnchanged, result)
+}
+
+// ✅ Test: RoleBinding Subjects Updated
+func TestApplyRoleBinding_SubjectsUpdated(t *testing.T) {
+	existing := convertRoleBinding("test-namespace", testRoleBinding())
+	existing.Subjects[0].Name = "someone-else"
+
+	clientset := fake.NewSimpleClientset(existing)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(
+		context.Background(),
+		"test-namespace",
+		testRoleBinding(),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.RoleBindingUpdated, result)
+
+	roleBinding, err := clientset.RbacV1().
+		RoleBindings("test-namespace").
+		Get(context.Background(), "onyxia-user", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "oidc:test-user", roleBinding.Subjects[0].Name)
+}
+
+// ✅ Test: RoleBinding recreated when the ClusterRole changes
+func TestApplyRoleBinding_RoleRefChanged(t *testing.T) {
+	existing := convertRoleBinding("test-namespace", testRoleBinding())
+	existing.RoleRef.Name = "view"
+
+	clientset := fake.NewSimpleClientset(existing)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(
+		context.Background(),
+		"test-namespace",
+		testRoleBinding(),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.RoleBindingUpdated, result)
+
+	roleBinding, err := clientset.RbacV1().
+		RoleBindings("test-namespace").
+		Get(context.Background(), "onyxia-user", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", roleBinding.RoleRef.Name)
+}
+
+// ✅ Test: RoleBinding is Ignored Due to Annotation
+func TestApplyRoleBinding_Ignored(t *testing.T) {
+	existing := convertRoleBinding("test-namespace", testRoleBinding())
+	existing.RoleRef.Name = "view"
+	existing.Annotations = map[string]string{IgnoreAnnotation: "true"}
+
+	clientset := fake.NewSimpleClientset(existing)
+	service := NewKubernetesNamespaceService(clientset)
+
+	result, err := service.ApplyRoleBinding(
+		context.Background(),
+		"test-namespace",
+		testRoleBinding(),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.RoleBindingIgnored, result)
+}
+
+// ❌ Test: Failure When Checking for an Existing RoleBinding
+func TestApplyRoleBinding_FailureGet(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor("get", "rolebindings",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to get role binding")
+		})
+
+	result, err := service.ApplyRoleBinding(
+		context.Background(),
+		"test-namespace",
+		testRoleBinding(),
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.RoleBindingApplicationResult(""), result)
+	assert.Contains(t, err.Error(), "failed to get role binding")
+}
+
+// ❌ Test: Failure When Creating a RoleBinding
+func TestApplyRoleBinding_FailureCreate(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor("create", "rolebindings",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to create role binding")
+		})
+
+	result, err := service.ApplyRoleBinding(
+		context.Background(),
+		"test-namespace",
+		testRoleBinding(),
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, interfaces.RoleBindingApplicationResult(""), result)
+	assert.Contains(t, err.Error(), "failed to create role binding")
+}
+
+// ✅ Test: Prune Member RoleBindings Not Seen Recently
+func TestPruneRoleBindings(t *testing.T) {
+	now := time.Now()
+	roleBinding := func(name string, seenAt time.Time) *rbacv1.RoleBinding {
+		return convertRoleBinding("projet-test", &domain.RoleBinding{
+			Name:   name,
+			Labels: map[string]string{"onyxia.sh/rbac-member": name},
+			Annotations: map[string]string{
+				domain.MemberSeenAtAnnotation: seenAt.Format(time.RFC3339),
+			},
+		})
+	}
+
+	recent := roleBinding("recent", now.Add(-time.Hour))
+	expired := roleBinding("expired", now.Add(-48*time.Hour))
+	ignored := roleBinding("ignored", now.Add(-48*time.Hour))
+	ignored.Annotations[IgnoreAnnotation] = "true"
+	// 🔹 Applied before the seen-at annotation: its creation time is used
+	legacy := convertRoleBinding("projet-test", &domain.RoleBinding{
+		Name:   "legacy",
+		Labels: map[string]string{"onyxia.sh/rbac-member": "legacy"},
+	})
+	legacy.CreationTimestamp = metav1.NewTime(now.Add(-48 * time.Hour))
+	group := convertRoleBinding("projet-test", &domain.RoleBinding{Name: "onyxia-group"})
+	otherNamespace := roleBinding("expired", now.Add(-48*time.Hour))
+	otherNamespace.Namespace = "projet-other"
+
+	clientset := fake.NewSimpleClientset(recent, expired, ignored, legacy, group, otherNamespace)
+	service := NewKubernetesNamespaceService(clientset)
+
+	count, err := service.PruneRoleBindings(
+		context.Background(),
+		"projet-test",
+		"onyxia.sh/rbac-member",
+		now.Add(-24*time.Hour),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	roleBindings, _ := clientset.RbacV1().
+		RoleBindings("projet-test").
+		List(context.Background(), metav1.ListOptions{})
+	var names []string
+	for _, roleBinding := range roleBindings.Items {
+		names = append(names, roleBinding.Name)
+	}
+	assert.ElementsMatch(t, []string{"recent", "ignored", "onyxia-group"}, names)
+
+	_, err = clientset.RbacV1().
+		RoleBindings("projet-other").
+		Get(context.Background(), "expired", metav1.GetOptions{})
+	assert.NoError(t, err, "Expected role bindings of other namespaces to be kept")
+
+	// 🔹 Only the namespace is listed, not the whole cluster
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "list" {
+			assert.Equal(t, "projet-test", action.GetNamespace())
+		}
+	}
+}
+
+// ✅ Test: The Seen-At Annotation of a Member is Refreshed
+func TestApplyRoleBinding_SeenAtUpdated(t *testing.T) {
+	roleBinding := testRoleBinding()
+	roleBinding.Annotations = map[string]string{
+		domain.MemberSeenAtAnnotation: "2026-01-01T00:00:00Z",
+	}
+	clientset := fake.NewSimpleClientset(convertRoleBinding("test-namespace", roleBinding))
+	service := NewKubernetesNamespaceService(clientset)
+
+	roleBinding.Annotations = map[string]string{
+		domain.MemberSeenAtAnnotation: "2026-01-02T00:00:00Z",
+	}
+	result, err := service.ApplyRoleBinding(context.Background(), "test-namespace", roleBinding)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.RoleBindingUpdated, result)
+
+	existing, err := clientset.RbacV1().
+		RoleBindings("test-namespace").
+		Get(context.Background(), "onyxia-user", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-01-02T00:00:00Z", existing.Annotations[domain.MemberSeenAtAnnotation])
+}
+
+// ❌ Test: Failure When Listing RoleBindings
+func TestPruneRoleBindings_FailureList(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	service := NewKubernetesNamespaceService(clientset)
+
+	clientset.PrependReactor("list", "rolebindings",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("failed to list role bindings")
+		})
+
+	count, err := service.PruneRoleBindings(
+		context.Background(),
+		"test-namespace",
+		"onyxia.sh/rbac-member",
+		time.Now(),
+	)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, count)
+	assert.Contains(t, err.Error(), "failed to list role bindings")
+}
@@ -4,22 +4,66 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 )
 
 const QuotaName string = "onyxia-quota"
-const IgnoreQuotaAnnotation string = "onyxia.sh/ignore"
+const IgnoreAnnotation string = "onyxia.sh/ignore"
+const IgnoreQuotaAnnotation string = IgnoreAnnotation
+const ScopedQuotaPrefix string = QuotaName + "-"
+
+// IgnoreKeysAnnotation lists the resources of a quota to leave as they are, e.g.
+// "limits.nvidia.com/gpu,requests.storage", while the other ones are still reconciled.
+const IgnoreKeysAnnotation string = "onyxia.sh/ignore-keys"
+
+var resourceQuotaScopes = []v1.ResourceQuotaScope{
+	v1.ResourceQuotaScopeTerminating,
+	v1.ResourceQuotaScopeNotTerminating,
+	v1.ResourceQuotaScopeBestEffort,
+	v1.ResourceQuotaScopeNotBestEffort,
+	v1.ResourceQuotaScopePriorityClass,
+	v1.ResourceQuotaScopeCrossNamespacePodAffinity,
+	v1.ResourceQuotaScopeVolumeAttributesClass,
+}
+
+var scopeSelectorOperators = []v1.ScopeSelectorOperator{
+	v1.ScopeSelectorOpIn,
+	v1.ScopeSelectorOpNotIn,
+	v1.ScopeSelectorOpExists,
+	v1.ScopeSelectorOpDoesNotExist,
+}
+
+// dryRun returns the DryRun option of write requests made on behalf of a dry-run context.
+func dryRun(ctx context.Context) []string {
+	if domain.IsDryRun(ctx) {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
 
 type KubernetesNamespaceService struct {
 	clientset k8s.Interface
+	cache     *NamespaceCache // read instead of the API server once synced, when set
+	// legacyFieldManagers are the field managers of versions prior to server-side apply
+	legacyFieldManagers sets.Set[string]
 }
 
 func NewKubernetesNamespaceService(clientset k8s.Interface) interfaces.NamespaceService {
@@ -28,6 +72,52 @@ func NewKubernetesNamespaceService(clientset k8s.Interface) interfaces.Namespace
 	}
 }
 
+// NewCachedKubernetesNamespaceService reads the namespaces and quotas created by the onboarding
+// from cache, so that onboardings changing nothing only send write requests for actual changes.
+// The fields set by legacyFieldManagers are handed over to FieldManager, see upgradeFieldManager.
+func NewCachedKubernetesNamespaceService(
+	clientset k8s.Interface,
+	cache *NamespaceCache,
+	legacyFieldManagers []string,
+) interfaces.NamespaceService {
+	return &KubernetesNamespaceService{
+		clientset:           clientset,
+		cache:               cache,
+		legacyFieldManagers: sets.New(legacyFieldManagers...),
+	}
+}
+
+// getNamespace returns the namespace from cache, or from the API server when it is not cached.
+func (s *KubernetesNamespaceService) getNamespace(
+	ctx context.Context,
+	name string,
+) (*v1.Namespace, error) {
+	if s.cache.HasSynced() {
+		if namespace := s.cache.namespace(name); namespace != nil {
+			return namespace, nil
+		}
+	}
+	return s.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
+
+// getResourceQuota returns the quota from cache when fromCache is set and it is cached, or from
+// the API server.
+func (s *KubernetesNamespaceService) getResourceQuota(
+	ctx context.Context,
+	namespace string,
+	name string,
+	fromCache bool,
+) (*v1.ResourceQuota, error) {
+	if fromCache && s.cache.HasSynced() {
+		if quota := s.cache.quota(namespace, name); quota != nil {
+			return quota, nil
+		}
+	}
+	return s.clientset.CoreV1().ResourceQuotas(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// CreateNamespace applies the annotations and labels of the onboarding to the namespace, creating
+// it if needed. Annotations and labels set by others are kept.
 func (s *KubernetesNamespaceService) CreateNamespace(
 	ctx context.Context,
 	name string,
@@ -35,132 +125,543 @@ func (s *KubernetesNamespaceService) CreateNamespace(
 	labels map[string]string,
 ) (interfaces.NamespaceCreationResult, error) {
 	namespacesClient := s.clientset.CoreV1().Namespaces()
+	labels = labelsWithOwnership(labels)
 
-	namespace := &v1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        name,
-			Annotations: annotations,
-			Labels:      labels,
-		},
+	existing, err := s.getNamespace(ctx, name)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get namespace: %w", err)
 	}
+	exists := err == nil
 
-	_, err := namespacesClient.Create(ctx, namespace, metav1.CreateOptions{})
+	if exists {
+		owned, err := checkOwnership(existing, annotations[domain.IdentityAnnotation])
+		if err != nil {
+			return "", err
+		}
+		if !owned {
+			slog.WarnContext(ctx, "⚠️ Namespace was not created by onboarding",
+				slog.String("namespace", name),
+			)
+			return interfaces.NamespaceNotOwned, nil
+		}
 
-	if errors.IsAlreadyExists(err) {
+		// 🔹 The owner of an archived namespace is back
+		restored := existing.Labels[ArchivedLabel] == "true"
+		if restored {
+			if err := s.restoreNamespace(ctx, name); err != nil {
+				return "", err
+			}
+		}
 
-		if len(annotations) == 0 {
+		// 🔹 Nothing to change, spare the API server a write request
+		if !restored && isApplied(existing, annotations, labels) {
 			return interfaces.NamespaceAlreadyExists, nil
 		}
 
-		// 🔹 We update annotations (even if it might be unnecessary)
-		//    - To check if an update is actually required, we would need to make a `Get()` request.
-		//    - To avoid extra API calls, we simply apply the patch directly.
-		//    - Kubernetes will internally handle cases where no actual change is needed.
+		err = upgradeFieldManager(ctx, existing, s.legacyFieldManagers, namespacesClient.Patch)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	applied, err := namespacesClient.Apply(
+		ctx,
+		corev1ac.Namespace(name).WithAnnotations(annotations).WithLabels(labels),
+		applyOptions(ctx),
+	)
+	if errors.IsConflict(err) {
+		return "", conflictError("namespace", name, err)
+	}
+	if err != nil && exists {
+		return "", fmt.Errorf("failed to update namespace annotations: %w", err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create namespace: %w", err)
+	}
+
+	if !exists {
+		return interfaces.NamespaceCreated, nil
+	}
+	if maps.Equal(existing.Annotations, applied.Annotations) &&
+		maps.Equal(existing.Labels, applied.Labels) {
+		return interfaces.NamespaceAlreadyExists, nil
+	}
+	return interfaces.NamespaceAnnotationsUpdated, nil
+}
+
+func (s *KubernetesNamespaceService) ApplyResourceQuotas(
+	ctx context.Context,
+	namespace string,
+	quota *domain.Quota,
+) (interfaces.QuotaApplicationResult, error) {
+	hardLimits, err := convertQuotaToResourceMap(*quota)
+
+	if err != nil {
+		return "", fmt.Errorf("error converting quota to ResourceQuota: %w", err)
+	}
+
+	scopedQuotas, err := convertScopedQuotas(namespace, quota.Scoped)
+	if err != nil {
+		return "", fmt.Errorf("error converting scoped quotas to ResourceQuotas: %w", err)
+	}
 
-		patchData := map[string]interface{}{
-			"metadata": map[string]interface{}{
-				"annotations": annotations,
-				"labels":      labels,
+	// ✅ If no valid quotas exist, there is no main quota to apply
+	result := interfaces.QuotaUnchanged
+	if len(hardLimits) > 0 {
+		result, err = s.applyResourceQuota(ctx, &v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      QuotaName,
+				Namespace: namespace,
+				Labels:    managedLabels(),
 			},
+			Spec: v1.ResourceQuotaSpec{
+				Hard: hardLimits,
+			}})
+		if err != nil {
+			return "", err
 		}
+	}
+
+	for _, scopedQuota := range scopedQuotas {
+		scopedResult, err := s.applyResourceQuota(ctx, scopedQuota)
+		if err != nil {
+			return "", err
+		}
+		slog.InfoContext(ctx, "🔹 Applied scoped resource quota",
+			slog.String("namespace", namespace),
+			slog.String("quota", scopedQuota.Name),
+			slog.String("result", string(scopedResult)),
+		)
+	}
+
+	if err := s.pruneScopedQuotas(ctx, namespace, scopedQuotas); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func (s *KubernetesNamespaceService) applyResourceQuota(
+	ctx context.Context,
+	resourceQuota *v1.ResourceQuota,
+) (interfaces.QuotaApplicationResult, error) {
+	var result interfaces.QuotaApplicationResult
+
+	// 🔹 The quota may change between the Get and the upgrade of its field managers, e.g. on
+	// concurrent onboardings from other replicas, or the cache may lag behind, in which case it
+	// is read again from the API server
+	fromCache := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		result, err = s.reconcileResourceQuota(ctx, resourceQuota, fromCache)
+		fromCache = false
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+func (s *KubernetesNamespaceService) reconcileResourceQuota(
+	ctx context.Context,
+	resourceQuota *v1.ResourceQuota,
+	fromCache bool,
+) (interfaces.QuotaApplicationResult, error) {
+	quotasClient := s.clientset.CoreV1().ResourceQuotas(resourceQuota.Namespace)
 
-		patchBytes, err := json.Marshal(patchData)
+	existingQuota, err := s.getResourceQuota(
+		ctx,
+		resourceQuota.Namespace,
+		resourceQuota.Name,
+		fromCache,
+	)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", fmt.Errorf(
+			"unexpected error checking for existing quota: %w",
+			err,
+		)
+	}
+
+	// If quota doesn't exist, create it
+	if err != nil {
+		result, err := s.applyResourceQuotaSpec(ctx, resourceQuota, interfaces.QuotaCreated)
 		if err != nil {
-			return "", fmt.Errorf("failed to marshal patch data: %w", err)
+			return "", fmt.Errorf("failed to create resource quota: %w", err)
+		}
+		return result, nil
+	}
+
+	// Ignore quota if marked as ignored
+	if ignore, ok := existingQuota.Annotations[IgnoreQuotaAnnotation]; ok && ignore == "true" {
+		return interfaces.QuotaIgnored, nil
+	}
+
+	// Keep the resources an admin asked to leave as they are
+	ignoredKeys := parseIgnoreKeys(existingQuota.Annotations[IgnoreKeysAnnotation])
+	if len(ignoredKeys) > 0 {
+		resourceQuota = resourceQuota.DeepCopy()
+		for _, key := range ignoredKeys {
+			if value, ok := existingQuota.Spec.Hard[key]; ok {
+				resourceQuota.Spec.Hard[key] = value
+			} else {
+				delete(resourceQuota.Spec.Hard, key)
+			}
 		}
+	}
+
+	updated, unchanged := interfaces.QuotaUpdated, interfaces.QuotaUnchanged
+	if len(ignoredKeys) > 0 {
+		updated, unchanged = interfaces.QuotaPartiallyIgnored, interfaces.QuotaPartiallyIgnored
+	}
+
+	// If quota is unchanged, return early
+	if !quotasAreDifferent(existingQuota, resourceQuota) {
+		return unchanged, nil
+	}
+
+	// 🔹 Scopes are immutable, the quota has to be recreated
+	if scopesAreDifferent(existingQuota, resourceQuota) {
+		return s.recreateResourceQuota(ctx, existingQuota, resourceQuota, updated)
+	}
 
-		_, err = namespacesClient.Patch(
+	err = upgradeFieldManager(ctx, existingQuota, s.legacyFieldManagers, quotasClient.Patch)
+	if err != nil {
+		return "", err
+	}
+
+	// Update existing quota
+	result, err := s.applyResourceQuotaSpec(ctx, resourceQuota, updated)
+	if err != nil {
+		return "", fmt.Errorf("failed to update resource quota: %w", err)
+	}
+	return result, nil
+}
+
+// applyResourceQuotaSpec applies the labels and spec of the quota, returning result, or
+// ErrFieldManagerConflict when another field manager owns the fields to change.
+func (s *KubernetesNamespaceService) applyResourceQuotaSpec(
+	ctx context.Context,
+	resourceQuota *v1.ResourceQuota,
+	result interfaces.QuotaApplicationResult,
+) (interfaces.QuotaApplicationResult, error) {
+	_, err := s.clientset.CoreV1().ResourceQuotas(resourceQuota.Namespace).Apply(
+		ctx,
+		resourceQuotaApplyConfiguration(resourceQuota),
+		applyOptions(ctx),
+	)
+	if errors.IsConflict(err) {
+		return "", conflictError(
+			"resource quota",
+			resourceQuota.Namespace+"/"+resourceQuota.Name,
+			err,
+		)
+	}
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// recreateResourceQuota deletes the existing quota and applies the new one, keeping the
+// annotations of the existing quota, e.g. the ignore-keys annotation set by an admin.
+func (s *KubernetesNamespaceService) recreateResourceQuota(
+	ctx context.Context,
+	existingQuota *v1.ResourceQuota,
+	resourceQuota *v1.ResourceQuota,
+	result interfaces.QuotaApplicationResult,
+) (interfaces.QuotaApplicationResult, error) {
+	quotasClient := s.clientset.CoreV1().ResourceQuotas(resourceQuota.Namespace)
+
+	err := quotasClient.Delete(
+		ctx,
+		resourceQuota.Name,
+		metav1.DeleteOptions{DryRun: dryRun(ctx)},
+	)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to delete resource quota %s: %w", resourceQuota.Name, err)
+	}
+
+	// 🔹 In dry-run the quota was not deleted, so it cannot be created again
+	if domain.IsDryRun(ctx) {
+		return result, nil
+	}
+
+	result, err = s.applyResourceQuotaSpec(ctx, resourceQuota, result)
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource quota %s: %w", resourceQuota.Name, err)
+	}
+
+	// 🔹 Annotations are patched rather than applied, so that they are not owned by the apply
+	// field manager and removed by the next apply
+	if len(existingQuota.Annotations) > 0 {
+		patch, err := json.Marshal(map[string]any{
+			"metadata": map[string]any{"annotations": existingQuota.Annotations},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal quota annotations: %w", err)
+		}
+		_, err = quotasClient.Patch(
 			ctx,
-			name,
+			resourceQuota.Name,
 			types.MergePatchType,
-			patchBytes,
-			metav1.PatchOptions{},
+			patch,
+			metav1.PatchOptions{FieldManager: FieldManager},
 		)
 		if err != nil {
-			return "", fmt.Errorf("failed to update namespace annotations: %w", err)
+			return "", fmt.Errorf(
+				"failed to restore annotations of resource quota %s: %w",
+				resourceQuota.Name,
+				err,
+			)
+		}
+	}
+
+	return result, nil
+}
+
+// resourceQuotaApplyConfiguration returns the fields of the quota owned by the onboarding.
+func resourceQuotaApplyConfiguration(
+	resourceQuota *v1.ResourceQuota,
+) *corev1ac.ResourceQuotaApplyConfiguration {
+	spec := corev1ac.ResourceQuotaSpec().
+		WithHard(resourceQuota.Spec.Hard).
+		WithScopes(resourceQuota.Spec.Scopes...)
+
+	if selector := resourceQuota.Spec.ScopeSelector; selector != nil {
+		scopeSelector := corev1ac.ScopeSelector()
+		for _, requirement := range selector.MatchExpressions {
+			scopeSelector.WithMatchExpressions(corev1ac.ScopedResourceSelectorRequirement().
+				WithScopeName(requirement.ScopeName).
+				WithOperator(requirement.Operator).
+				WithValues(requirement.Values...))
 		}
-		return interfaces.NamespaceAnnotationsUpdated, nil
+		spec.WithScopeSelector(scopeSelector)
 	}
 
+	return corev1ac.ResourceQuota(resourceQuota.Name, resourceQuota.Namespace).
+		WithLabels(resourceQuota.Labels).
+		WithSpec(spec)
+}
+
+// pruneScopedQuotas deletes the scoped quotas that are no longer part of the quota profile.
+func (s *KubernetesNamespaceService) pruneScopedQuotas(
+	ctx context.Context,
+	namespace string,
+	keep []*v1.ResourceQuota,
+) error {
+	quotasClient := s.clientset.CoreV1().ResourceQuotas(namespace)
+
+	existing, err := s.listResourceQuotas(ctx, namespace)
 	if err != nil {
-		return "", fmt.Errorf("failed to create namespace: %w", err)
+		return err
+	}
+
+	for _, quota := range existing {
+		if !strings.HasPrefix(quota.Name, ScopedQuotaPrefix) {
+			continue
+		}
+		if containsQuota(keep, quota.Name) {
+			continue
+		}
+		if quota.Annotations[IgnoreQuotaAnnotation] == "true" {
+			continue
+		}
+
+		err := quotasClient.Delete(ctx, quota.Name, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete resource quota %s: %w", quota.Name, err)
+		}
+		slog.InfoContext(ctx, "✅ Deleted scoped resource quota no longer configured",
+			slog.String("namespace", namespace),
+			slog.String("quota", quota.Name),
+		)
 	}
 
-	return interfaces.NamespaceCreated, nil
+	return nil
 }
 
-func (s *KubernetesNamespaceService) ApplyResourceQuotas(
+// listResourceQuotas returns the quotas created by the onboarding in the namespace.
+func (s *KubernetesNamespaceService) listResourceQuotas(
+	ctx context.Context,
+	namespace string,
+) ([]v1.ResourceQuota, error) {
+	if s.cache.HasSynced() {
+		return s.cache.namespaceQuotas(namespace), nil
+	}
+
+	quotas, err := s.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(managedLabels()).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	return quotas.Items, nil
+}
+
+// DeleteResourceQuota deletes the main quota of the namespace when it was created by the
+// onboarding service, so that a profile without limits does not leave a stale one behind.
+func (s *KubernetesNamespaceService) DeleteResourceQuota(
 	ctx context.Context,
 	namespace string,
-	quota *domain.Quota,
 ) (interfaces.QuotaApplicationResult, error) {
 	quotasClient := s.clientset.CoreV1().ResourceQuotas(namespace)
 
-	hardLimits, err := convertQuotaToResourceMap(*quota)
-
+	existingQuota, err := quotasClient.Get(ctx, QuotaName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return interfaces.QuotaUnchanged, nil
+	}
 	if err != nil {
-		return "", fmt.Errorf("error converting quota to ResourceQuota: %w", err)
+		return "", fmt.Errorf("unexpected error checking for existing quota: %w", err)
+	}
+
+	if existingQuota.Annotations[IgnoreQuotaAnnotation] == "true" {
+		return interfaces.QuotaIgnored, nil
 	}
 
-	// ✅ If no valid quotas exist, return early
-	if len(hardLimits) == 0 {
+	// 🔹 A quota of the same name created by someone else is not ours to delete
+	if !isManaged(existingQuota.Labels) {
+		slog.WarnContext(ctx, "⚠️ Resource quota is not managed by the onboarding, keeping it",
+			slog.String("namespace", namespace),
+			slog.String("quota", QuotaName),
+		)
 		return interfaces.QuotaUnchanged, nil
 	}
 
-	resourceQuota := &v1.ResourceQuota{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      QuotaName,
-			Namespace: namespace,
-			Labels: map[string]string{
-				"created-by": "onyxia",
-			},
-		},
-		Spec: v1.ResourceQuotaSpec{
-			Hard: hardLimits,
-		}}
+	err = quotasClient.Delete(ctx, QuotaName, metav1.DeleteOptions{DryRun: dryRun(ctx)})
+	if err != nil && !errors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to delete resource quota: %w", err)
+	}
 
-	existingQuota, err := quotasClient.Get(ctx, QuotaName, metav1.GetOptions{})
+	return interfaces.QuotaDeleted, nil
+}
+
+func convertScopedQuotas(
+	namespace string,
+	scopedQuotas []domain.ScopedQuota,
+) ([]*v1.ResourceQuota, error) {
+	result := make([]*v1.ResourceQuota, 0, len(scopedQuotas))
+
+	for _, scopedQuota := range scopedQuotas {
+		name := ScopedQuotaPrefix + scopedQuota.Name
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return nil, fmt.Errorf(
+				"invalid scoped quota name %q: %s",
+				name,
+				strings.Join(errs, ", "),
+			)
+		}
+		if containsQuota(result, name) {
+			return nil, fmt.Errorf("scoped quota %q is defined twice", scopedQuota.Name)
+		}
 
-	if err == nil {
-		// Ignore quota if marked as ignored
-		if ignore, ok := existingQuota.Annotations[IgnoreQuotaAnnotation]; ok && ignore == "true" {
-			return interfaces.QuotaIgnored, nil
+		hard, err := convertQuotaToResourceMap(domain.Quota{Resources: scopedQuota.Hard})
+		if err != nil {
+			return nil, fmt.Errorf("invalid scoped quota %q: %w", scopedQuota.Name, err)
+		}
+		if len(hard) == 0 {
+			return nil, fmt.Errorf("scoped quota %q has no resource", scopedQuota.Name)
 		}
 
-		// If quota is unchanged, return early
-		if !quotasAreDifferent(existingQuota, resourceQuota) {
-			return interfaces.QuotaUnchanged, nil
+		spec := v1.ResourceQuotaSpec{Hard: hard}
+		for _, scope := range scopedQuota.Scopes {
+			if !slices.Contains(resourceQuotaScopes, v1.ResourceQuotaScope(scope)) {
+				return nil, fmt.Errorf(
+					"invalid scope %q of scoped quota %q",
+					scope,
+					scopedQuota.Name,
+				)
+			}
+			spec.Scopes = append(spec.Scopes, v1.ResourceQuotaScope(scope))
 		}
 
-		// Update existing quota
-		existingQuota.Spec = resourceQuota.Spec
-		_, updateErr := quotasClient.Update(ctx, existingQuota, metav1.UpdateOptions{})
-		if updateErr != nil {
-			return "", fmt.Errorf(
-				"failed to update resource quota: %w",
-				updateErr,
+		if len(scopedQuota.ScopeSelector) > 0 {
+			spec.ScopeSelector = &v1.ScopeSelector{}
+		}
+		for _, requirement := range scopedQuota.ScopeSelector {
+			if !slices.Contains(resourceQuotaScopes, v1.ResourceQuotaScope(requirement.ScopeName)) {
+				return nil, fmt.Errorf(
+					"invalid scope %q of scoped quota %q",
+					requirement.ScopeName,
+					scopedQuota.Name,
+				)
+			}
+			operator := v1.ScopeSelectorOperator(requirement.Operator)
+			if !slices.Contains(scopeSelectorOperators, operator) {
+				return nil, fmt.Errorf(
+					"invalid scope selector operator %q of scoped quota %q",
+					requirement.Operator,
+					scopedQuota.Name,
+				)
+			}
+			spec.ScopeSelector.MatchExpressions = append(
+				spec.ScopeSelector.MatchExpressions,
+				v1.ScopedResourceSelectorRequirement{
+					ScopeName: v1.ResourceQuotaScope(requirement.ScopeName),
+					Operator:  operator,
+					Values:    requirement.Values,
+				},
 			)
 		}
 
-		return interfaces.QuotaUpdated, nil
+		result = append(result, &v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    managedLabels(),
+			},
+			Spec: spec,
+		})
 	}
 
-	// If quota doesn't exist, create it
-	if errors.IsNotFound(err) {
-		_, err = quotasClient.Create(ctx, resourceQuota, metav1.CreateOptions{})
-		if err != nil {
-			return "", fmt.Errorf("failed to create resource quota: %w", err)
-		}
-		return interfaces.QuotaCreated, nil
+	return result, nil
+}
+
+// managedLabels are set on every object created by the onboarding service. On namespaces, they
+// mark the ones the service owns.
+func managedLabels() map[string]string {
+	return map[string]string{
+		"created-by": "onyxia",
 	}
+}
 
-	return "", fmt.Errorf(
-		"unexpected error checking for existing quota: %w",
-		err,
-	)
+// labelsWithOwnership returns the labels along with the managed labels marking the namespace
+// as owned by the onboarding.
+func labelsWithOwnership(objectLabels map[string]string) map[string]string {
+	result := maps.Clone(objectLabels)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	maps.Copy(result, managedLabels())
+	return result
+}
+
+// checkOwnership tells whether the onboarding owns the namespace: it carries the managed labels,
+// or, for namespaces onboarded before they existed, the identity annotation of identity. As two
+// identities may be normalized to the same name, a namespace of another identity is a collision.
+func checkOwnership(namespace *v1.Namespace, identity string) (bool, error) {
+	owner := namespace.Annotations[domain.IdentityAnnotation]
+	if identity != "" && owner != "" && owner != identity {
+		return false, fmt.Errorf(
+			"%w: namespace %s belongs to %s",
+			domain.ErrNamespaceCollision,
+			namespace.Name,
+			owner,
+		)
+	}
+	return isManaged(namespace.Labels) || (identity != "" && owner == identity), nil
+}
+
+func isManaged(objectLabels map[string]string) bool {
+	return labels.SelectorFromSet(managedLabels()).Matches(labels.Set(objectLabels))
 }
 
 func quotasAreDifferent(existing, newQuota *v1.ResourceQuota) bool {
+	if scopesAreDifferent(existing, newQuota) {
+		return true
+	}
+
 	if len(existing.Spec.Hard) != len(newQuota.Spec.Hard) {
 		return true
 	}
@@ -175,6 +676,26 @@ func quotasAreDifferent(existing, newQuota *v1.ResourceQuota) bool {
 	return false
 }
 
+// parseIgnoreKeys returns the resources listed in the ignore-keys annotation.
+func parseIgnoreKeys(annotation string) []v1.ResourceName {
+	var keys []v1.ResourceName
+	for _, key := range strings.Split(annotation, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, v1.ResourceName(key))
+		}
+	}
+	return keys
+}
+
+func containsQuota(quotas []*v1.ResourceQuota, name string) bool {
+	return slices.ContainsFunc(quotas, func(q *v1.ResourceQuota) bool { return q.Name == name })
+}
+
+func scopesAreDifferent(existing, newQuota *v1.ResourceQuota) bool {
+	return !equality.Semantic.DeepEqual(existing.Spec.Scopes, newQuota.Spec.Scopes) ||
+		!equality.Semantic.DeepEqual(existing.Spec.ScopeSelector, newQuota.Spec.ScopeSelector)
+}
+
 func convertQuotaToResourceMap(quota domain.Quota) (map[v1.ResourceName]resource.Quantity, error) {
 	quotaEntries := map[v1.ResourceName]string{
 		v1.ResourceRequestsMemory:                  quota.MemoryRequest,
@@ -189,17 +710,39 @@ func convertQuotaToResourceMap(quota domain.Quota) (map[v1.ResourceName]resource
 		v1.ResourceName("limits.nvidia.com/gpu"):   quota.GPULimit,
 	}
 
+	for name := range quota.Resources {
+		if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid resource name %q: %s", name, strings.Join(errs, ", "))
+		}
+		if quotaEntries[v1.ResourceName(name)] != "" {
+			return nil, fmt.Errorf("resource %q is set twice", name)
+		}
+		quotaEntries[v1.ResourceName(name)] = quota.Resources[name]
+	}
+
+	return parseResourceList(quotaEntries)
+}
+
+// ValidateQuota checks that the resource names, quantities and scopes of a quota are valid.
+func ValidateQuota(quota domain.Quota) error {
+	if _, err := convertQuotaToResourceMap(quota); err != nil {
+		return err
+	}
+	_, err := convertScopedQuotas("", quota.Scoped)
+	return err
+}
+
+func parseResourceList(entries map[v1.ResourceName]string) (v1.ResourceList, error) {
 	// ✅ Filter out empty values and create a new immutable map
-	hardLimits := make(map[v1.ResourceName]resource.Quantity, len(quotaEntries))
-	for key, value := range quotaEntries {
+	result := make(v1.ResourceList, len(entries))
+	for key, value := range entries {
 		if value != "" {
 			quantity, err := resource.ParseQuantity(value)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("invalid quantity %q for %s: %w", value, key, err)
 			}
-			hardLimits[key] = quantity
+			result[key] = quantity
 		}
 	}
-
-	return hardLimits, nil
+	return result, nil
 }
@@ -4,41 +4,97 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 )
 
+// legacyClientset returns a clientset holding objects written by a version of the service prior
+// to server-side apply.
+func legacyClientset(t *testing.T, objects ...runtime.Object) *fake.Clientset {
+	return clientsetManagedBy(t, "app", objects...)
+}
+
+// newLegacyService returns a service handing the fields of legacyClientset over to FieldManager.
+func newLegacyService(clientset *fake.Clientset) interfaces.NamespaceService {
+	return NewCachedKubernetesNamespaceService(clientset, nil, []string{"app", "main"})
+}
+
+// clientsetManagedBy returns a clientset holding objects created by the given field manager.
+func clientsetManagedBy(
+	t *testing.T,
+	fieldManager string,
+	objects ...runtime.Object,
+) *fake.Clientset {
+	clientset := fake.NewClientset()
+	for _, obj := range objects {
+		var err error
+		switch obj := obj.(type) {
+		case *v1.Namespace:
+			err = clientset.Tracker().Create(
+				v1.SchemeGroupVersion.WithResource("namespaces"),
+				obj,
+				"",
+				metav1.CreateOptions{FieldManager: fieldManager},
+			)
+		case *v1.ResourceQuota:
+			err = clientset.Tracker().Create(
+				v1.SchemeGroupVersion.WithResource("resourcequotas"),
+				obj,
+				obj.Namespace,
+				metav1.CreateOptions{FieldManager: fieldManager},
+			)
+		default:
+			t.Fatalf("unexpected object %T", obj)
+		}
+		assert.NoError(t, err)
+	}
+	return clientset
+}
+
+// isApply tells whether an action is a server-side apply request, rather than the upgrade of
+// legacy field managers.
+func isApply(action k8stesting.Action) bool {
+	patchAction, ok := action.(k8stesting.PatchActionImpl)
+	return ok && patchAction.PatchType == types.ApplyPatchType
+}
+
 // ✅ Test: Create Namespace Successfully
 func TestCreateNamespace_Success(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	result, err := service.CreateNamespace(context.Background(), "test-namespace", nil, nil)
 
 	assert.NoError(t, err)
 	assert.Equal(t, interfaces.NamespaceCreated, result)
 
-	_, err = clientset.CoreV1().
+	namespace, err := clientset.CoreV1().
 		Namespaces().
 		Get(context.Background(), "test-namespace", metav1.GetOptions{})
 	assert.NoError(t, err)
+	assert.Equal(t, managedLabels(), namespace.Labels)
 }
 
 // ✅ Test: Namespace Already Exists (No Annotation Change)
 func TestCreateNamespace_AlreadyExists(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"},
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
 	result, err := service.CreateNamespace(context.Background(), "test-namespace", nil, nil)
 
@@ -46,12 +102,39 @@ func TestCreateNamespace_AlreadyExists(t *testing.T) {
 	assert.Equal(t, interfaces.NamespaceAlreadyExists, result)
 }
 
+// ✅ Test: Dry-Run Namespace Creation
+func TestCreateNamespace_DryRun(t *testing.T) {
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
+
+	clientset.PrependReactor(
+		"patch",
+		"namespaces",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patchAction := action.(k8stesting.PatchActionImpl)
+			assert.Equal(t, types.ApplyPatchType, patchAction.PatchType)
+			assert.Equal(t, []string{metav1.DryRunAll}, patchAction.PatchOptions.DryRun)
+			return true, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}, nil
+		},
+	)
+
+	result, err := service.CreateNamespace(
+		domain.WithDryRun(context.Background()),
+		"test-namespace",
+		nil,
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceCreated, result)
+}
+
 // ✅ Test: Namespace Already Exists (No Annotations Given)
 func TestCreateNamespace_AlreadyExists_NoAnnotations(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"},
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
 	result, err := service.CreateNamespace(
 		context.Background(),
@@ -64,30 +147,149 @@ func TestCreateNamespace_AlreadyExists_NoAnnotations(t *testing.T) {
 	assert.Equal(t, interfaces.NamespaceAlreadyExists, result)
 }
 
+// ✅ Test: Labels of an Existing Namespace are Patched Without Annotations
+func TestCreateNamespace_AlreadyExists_UpdateLabels(t *testing.T) {
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"test-namespace",
+		nil,
+		map[string]string{"onyxia.sh/department": "dg75"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
+
+	namespace, _ := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "test-namespace", metav1.GetOptions{})
+	assert.Equal(t, "dg75", namespace.Labels["onyxia.sh/department"])
+}
+
+// ❌ Test: Namespace Already Belongs to Another Identity
+func TestCreateNamespace_Collision(t *testing.T) {
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-jean-dupont",
+			Annotations: map[string]string{domain.IdentityAnnotation: "user:jean-dupont"},
+		},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"user-jean-dupont",
+		map[string]string{domain.IdentityAnnotation: "user:Jean.Dupont"},
+		nil,
+	)
+
+	assert.ErrorIs(t, err, domain.ErrNamespaceCollision)
+	assert.Equal(t, interfaces.NamespaceCreationResult(""), result)
+}
+
+// ❌ Test: Namespace Not Created by Onboarding is Left Alone
+func TestCreateNamespace_NotOwned(t *testing.T) {
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube"},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"kube",
+		map[string]string{domain.IdentityAnnotation: "user:kube"},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceNotOwned, result)
+	assert.False(t, slices.ContainsFunc(clientset.Actions(), func(a k8stesting.Action) bool {
+		return a.GetVerb() == "patch"
+	}))
+}
+
+// ✅ Test: Namespace Onboarded Before the Ownership Label is Recognized by its Identity
+func TestCreateNamespace_OwnedByIdentity(t *testing.T) {
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-jean-dupont",
+			Annotations: map[string]string{domain.IdentityAnnotation: "user:Jean.Dupont"},
+		},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"user-jean-dupont",
+		map[string]string{domain.IdentityAnnotation: "user:Jean.Dupont"},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
+
+	namespace, err := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "user-jean-dupont", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "onyxia", namespace.Labels["created-by"])
+}
+
+// ❌ Test: Unmarked Namespace Named as by Previous Versions is Not Adopted
+func TestCreateNamespace_LegacyNameNotAdopted(t *testing.T) {
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-jdupont"},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"user-jdupont",
+		map[string]string{domain.IdentityAnnotation: "user:jdupont"},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceNotOwned, result)
+	namespace, _ := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "user-jdupont", metav1.GetOptions{})
+	assert.NotContains(t, namespace.Labels, "created-by")
+	assert.NotContains(t, namespace.Annotations, domain.IdentityAnnotation)
+}
+
 // ✅ Test: Update Annotations When Namespace Exists
 func TestCreateNamespace_UpdateAnnotations(t *testing.T) {
 	existingAnnotations := map[string]string{"old-key": "old-value"}
 	newAnnotations := map[string]string{"new-key": "new-value"}
 
-	clientset := fake.NewSimpleClientset(&v1.Namespace{
+	clientset := legacyClientset(t, &v1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        "test-namespace",
+			Labels:      managedLabels(),
 			Annotations: existingAnnotations,
 		},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
 	clientset.PrependReactor(
 		"patch",
 		"namespaces",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
-			patchAction, ok := action.(k8stesting.PatchAction)
-			assert.True(t, ok, "Expected PatchAction")
+			if !isApply(action) {
+				return false, nil, nil
+			}
+			patchAction := action.(k8stesting.PatchActionImpl)
+			assert.Equal(t, FieldManager, patchAction.PatchOptions.FieldManager)
 
-			var patch map[string]map[string]map[string]string
+			var patch v1.Namespace
 			err := json.Unmarshal(patchAction.GetPatch(), &patch)
 			assert.NoError(t, err)
-			assert.Equal(t, newAnnotations, patch["metadata"]["annotations"])
+			assert.Equal(t, newAnnotations, patch.Annotations)
 
 			return true, &v1.Namespace{
 				ObjectMeta: metav1.ObjectMeta{
@@ -109,12 +311,12 @@ func TestCreateNamespace_UpdateAnnotations(t *testing.T) {
 	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
 }
 
-// ❌ Test: Simulated API Failure (Create)
+// ❌ Test: Simulated API Failure (Apply on Create)
 func TestCreateNamespace_Failure(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
-	clientset.PrependReactor("create", "namespaces",
+	clientset.PrependReactor("patch", "namespaces",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
 			return true, nil, errors.New("simulated API failure")
 		})
@@ -126,16 +328,16 @@ func TestCreateNamespace_Failure(t *testing.T) {
 	assert.Contains(t, err.Error(), "simulated API failure")
 }
 
-// ❌ Test: Simulated API Failure (Patch)
+// ❌ Test: Simulated API Failure (Apply on Update)
 func TestCreateNamespace_FailurePatch(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"},
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: managedLabels()},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
 	clientset.PrependReactor("patch", "namespaces",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
-			return true, nil, errors.New("failed to patch annotations")
+			return isApply(action), nil, errors.New("failed to patch annotations")
 		})
 
 	result, err := service.CreateNamespace(
@@ -149,10 +351,176 @@ func TestCreateNamespace_FailurePatch(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to patch annotations")
 }
 
+// ❌ Test: Annotation Owned by Another Field Manager is Reported as a Conflict
+func TestCreateNamespace_Conflict(t *testing.T) {
+	clientset := clientsetManagedBy(t, "kubectl-edit", &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-namespace",
+			Labels:      managedLabels(),
+			Annotations: map[string]string{"onyxia.sh/department": "dg75"},
+		},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"test-namespace",
+		map[string]string{"onyxia.sh/department": "dg92"},
+		nil,
+	)
+
+	assert.ErrorIs(t, err, domain.ErrFieldManagerConflict)
+	assert.Contains(t, err.Error(), "kubectl-edit")
+	assert.Equal(t, interfaces.NamespaceCreationResult(""), result)
+
+	namespace, _ := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "test-namespace", metav1.GetOptions{})
+	assert.Equal(t, "dg75", namespace.Annotations["onyxia.sh/department"])
+}
+
+// ✅ Test: Fields of Other Field Managers are Kept
+func TestCreateNamespace_KeepsFieldsOfOtherManagers(t *testing.T) {
+	clientset := clientsetManagedBy(t, "kyverno", &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-namespace",
+			Labels: map[string]string{"created-by": "onyxia", "policy": "restricted"},
+		},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"test-namespace",
+		map[string]string{"new-key": "new-value"},
+		map[string]string{"onyxia.sh/department": "dg75"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
+
+	namespace, _ := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "test-namespace", metav1.GetOptions{})
+	assert.Equal(t, "restricted", namespace.Labels["policy"])
+	assert.Equal(t, "dg75", namespace.Labels["onyxia.sh/department"])
+	assert.Equal(t, "new-value", namespace.Annotations["new-key"])
+}
+
+// ✅ Test: Fields Set Before Server-Side Apply are Handed Over to the Field Manager
+func TestCreateNamespace_UpgradesLegacyFieldManager(t *testing.T) {
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-namespace",
+			Labels:      managedLabels(),
+			Annotations: map[string]string{"onyxia.sh/department": "dg75"},
+		},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.CreateNamespace(
+		context.Background(),
+		"test-namespace",
+		map[string]string{"onyxia.sh/department": "dg92"},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
+
+	namespace, _ := clientset.CoreV1().
+		Namespaces().
+		Get(context.Background(), "test-namespace", metav1.GetOptions{})
+	assert.Equal(t, "dg92", namespace.Annotations["onyxia.sh/department"])
+
+	var managers []string
+	for _, entry := range namespace.ManagedFields {
+		managers = append(managers, entry.Manager)
+	}
+	assert.Equal(t, []string{FieldManager}, managers)
+}
+
+// ❌ Test: Fields of a Manager Missing From the Legacy Field Managers are not Handed Over
+func TestCreateNamespace_UnknownLegacyFieldManager(t *testing.T) {
+	clientset := legacyClientset(t, &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-namespace",
+			Labels:      managedLabels(),
+			Annotations: map[string]string{"onyxia.sh/department": "dg75"},
+		},
+	})
+	service := NewCachedKubernetesNamespaceService(clientset, nil, []string{"main"})
+
+	_, err := service.CreateNamespace(
+		context.Background(),
+		"test-namespace",
+		map[string]string{"onyxia.sh/department": "dg92"},
+		nil,
+	)
+
+	assert.ErrorIs(t, err, domain.ErrFieldManagerConflict)
+	assert.Contains(t, err.Error(), `"app"`)
+}
+
+// ✅ Test: Legacy Field Managers are the Configured Managers of Update Entries
+func TestLegacyManagers(t *testing.T) {
+	namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "test-namespace",
+		ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: "app", Operation: metav1.ManagedFieldsOperationUpdate},
+			{Manager: "main", Operation: metav1.ManagedFieldsOperationApply},
+			{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate},
+			{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply},
+		},
+	}}
+
+	managers := legacyManagers(namespace, sets.New("app", "main"))
+
+	assert.Equal(t, []string{"app"}, sets.List(managers))
+}
+
+// ✅ Test: Annotations Patched Between Two Applies Are Kept
+func TestCreateNamespace_KeepsAnnotationsPatchedBetweenApplies(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	service := newLegacyService(clientset)
+
+	_, err := service.CreateNamespace(ctx, "test-namespace",
+		map[string]string{domain.LastLoginAnnotation: "1"}, nil)
+	assert.NoError(t, err)
+
+	// 🔹 Markers of the service itself, and an annotation of an administrator
+	manifestService := &KubernetesManifestService{clientset: clientset}
+	_, err = manifestService.PruneManifests(ctx, "test-namespace", nil)
+	assert.NoError(t, err)
+	idleService := &KubernetesIdleNamespaceService{clientset: clientset, now: time.Now}
+	assert.NoError(t, idleService.WarnNamespace(ctx, "test-namespace"))
+	_, err = clientset.CoreV1().Namespaces().Patch(
+		ctx,
+		"test-namespace",
+		types.MergePatchType,
+		[]byte(`{"metadata":{"annotations":{"onyxia.sh/department":"dg75"}}}`),
+		metav1.PatchOptions{FieldManager: "kubectl-annotate"},
+	)
+	assert.NoError(t, err)
+
+	result, err := service.CreateNamespace(ctx, "test-namespace",
+		map[string]string{domain.LastLoginAnnotation: "2"}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceAnnotationsUpdated, result)
+
+	namespace, _ := clientset.CoreV1().Namespaces().Get(ctx, "test-namespace", metav1.GetOptions{})
+	assert.Equal(t, "2", namespace.Annotations[domain.LastLoginAnnotation])
+	assert.Equal(t, "[]", namespace.Annotations[ManifestsAnnotation])
+	assert.Contains(t, namespace.Annotations, IdleWarningAnnotation)
+	assert.Equal(t, "dg75", namespace.Annotations["onyxia.sh/department"])
+}
+
 // ✅ Test: Apply Resource Quotas Successfully
 func TestApplyResourceQuotas_Success(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	quota := &domain.Quota{MemoryRequest: "10Gi", CPURequest: "10"}
 
@@ -163,8 +531,34 @@ func TestApplyResourceQuotas_Success(t *testing.T) {
 }
 
 // ✅ Test: Quota Already Exists with Unchanged Values
+// ✅ Test: Dry-Run Quota Creation
+func TestApplyResourceQuotas_DryRun(t *testing.T) {
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
+
+	clientset.PrependReactor(
+		"patch",
+		"resourcequotas",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patchAction := action.(k8stesting.PatchActionImpl)
+			assert.Equal(t, types.ApplyPatchType, patchAction.PatchType)
+			assert.Equal(t, []string{metav1.DryRunAll}, patchAction.PatchOptions.DryRun)
+			return true, &v1.ResourceQuota{}, nil
+		},
+	)
+
+	result, err := service.ApplyResourceQuotas(
+		domain.WithDryRun(context.Background()),
+		"test-namespace",
+		&domain.Quota{CPURequest: "1"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaCreated, result)
+}
+
 func TestApplyResourceQuotas_UnchangedQuota(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.ResourceQuota{
+	clientset := legacyClientset(t, &v1.ResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
 		Spec: v1.ResourceQuotaSpec{
 			Hard: map[v1.ResourceName]resource.Quantity{
@@ -172,7 +566,7 @@ func TestApplyResourceQuotas_UnchangedQuota(t *testing.T) {
 			},
 		},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
 	quota := &domain.Quota{MemoryRequest: "10Gi"} // Same values as existing
 
@@ -184,7 +578,7 @@ func TestApplyResourceQuotas_UnchangedQuota(t *testing.T) {
 
 // ✅ Test: Quota is Ignored Due to Annotation
 func TestApplyResourceQuotas_IgnoredQuota(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.ResourceQuota{
+	clientset := legacyClientset(t, &v1.ResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      QuotaName,
 			Namespace: "test-namespace",
@@ -193,7 +587,7 @@ func TestApplyResourceQuotas_IgnoredQuota(t *testing.T) {
 			},
 		},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
 	quota := &domain.Quota{MemoryRequest: "10Gi"}
 
@@ -205,8 +599,8 @@ func TestApplyResourceQuotas_IgnoredQuota(t *testing.T) {
 
 // ❌ Test: Failure When Checking for an Existing Quota
 func TestApplyResourceQuotas_FailureCheck(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	clientset.PrependReactor(
 		"get",
@@ -227,11 +621,11 @@ func TestApplyResourceQuotas_FailureCheck(t *testing.T) {
 
 // ❌ Test: Failure When Creating a Quota
 func TestApplyResourceQuotas_FailureCreate(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	clientset.PrependReactor(
-		"create",
+		"patch",
 		"resourcequotas",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
 			return true, nil, errors.New("failed to create quota")
@@ -248,7 +642,7 @@ func TestApplyResourceQuotas_FailureCreate(t *testing.T) {
 }
 
 func TestApplyResourceQuotas_QuotaUpdated(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.ResourceQuota{
+	clientset := legacyClientset(t, &v1.ResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
 		Spec: v1.ResourceQuotaSpec{
 			Hard: map[v1.ResourceName]resource.Quantity{
@@ -258,34 +652,91 @@ func TestApplyResourceQuotas_QuotaUpdated(t *testing.T) {
 			},
 		},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
+	quota := &domain.Quota{MemoryRequest: "10Gi"} // 👈 Updated quota
+
+	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaUpdated, result)
+
+	updated, _ := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), QuotaName, metav1.GetOptions{})
+	assert.True(t, updated.Spec.Hard[v1.ResourceRequestsMemory].Equal(resource.MustParse("10Gi")))
+}
+
+// ❌ Test: Quota Value Owned by Another Field Manager is Reported as a Conflict
+func TestApplyResourceQuotas_Conflict(t *testing.T) {
+	clientset := clientsetManagedBy(t, "kubectl-edit", &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
+		Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{
+			v1.ResourceRequestsMemory: resource.MustParse("5Gi"),
+		}},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{MemoryRequest: "10Gi"},
+	)
+
+	assert.ErrorIs(t, err, domain.ErrFieldManagerConflict)
+	assert.Contains(t, err.Error(), "kubectl-edit")
+	assert.Equal(t, interfaces.QuotaApplicationResult(""), result)
+
+	quota, _ := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), QuotaName, metav1.GetOptions{})
+	assert.True(t, quota.Spec.Hard[v1.ResourceRequestsMemory].Equal(resource.MustParse("5Gi")))
+}
+
+// ✅ Test: Quota Changed Concurrently is Read Again
+func TestApplyResourceQuotas_RetryOnConflict(t *testing.T) {
+	clientset := legacyClientset(t, &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
+		Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{
+			v1.ResourceRequestsMemory: resource.MustParse("5Gi"),
+		}},
+	})
+	service := newLegacyService(clientset)
+
+	upgrades := 0
 	clientset.PrependReactor(
-		"update",
+		"patch",
 		"resourcequotas",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
-			return true, &v1.ResourceQuota{
-				ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
-				Spec: v1.ResourceQuotaSpec{
-					Hard: map[v1.ResourceName]resource.Quantity{
-						v1.ResourceRequestsMemory: resource.MustParse("10Gi"),
-					},
-				},
-			}, nil
+			if action.(k8stesting.PatchActionImpl).PatchType != types.JSONPatchType {
+				return false, nil, nil
+			}
+			upgrades++
+			if upgrades > 1 {
+				return false, nil, nil
+			}
+			return true, nil, apierrors.NewConflict(
+				v1.Resource("resourcequotas"),
+				QuotaName,
+				errors.New("the object has been modified"),
+			)
 		},
 	)
 
-	quota := &domain.Quota{MemoryRequest: "10Gi"} // 👈 Updated quota
-
-	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
+	result, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{MemoryRequest: "10Gi"},
+	)
 
 	assert.NoError(t, err)
 	assert.Equal(t, interfaces.QuotaUpdated, result)
+	assert.Equal(t, 2, upgrades)
 }
 
 func TestApplyResourceQuotas_UnexpectedGetError(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	clientset.PrependReactor(
 		"get",
@@ -304,7 +755,7 @@ func TestApplyResourceQuotas_UnexpectedGetError(t *testing.T) {
 	assert.Contains(t, err.Error(), "unexpected error checking for existing quota")
 }
 func TestApplyResourceQuotas_FailureUpdate(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.ResourceQuota{
+	clientset := legacyClientset(t, &v1.ResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
 		Spec: v1.ResourceQuotaSpec{
 			Hard: map[v1.ResourceName]resource.Quantity{
@@ -314,14 +765,14 @@ func TestApplyResourceQuotas_FailureUpdate(t *testing.T) {
 			},
 		},
 	})
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
-	// Simulate an API failure when calling `Update()`
+	// Simulate an API failure when applying the quota
 	clientset.PrependReactor(
-		"update",
+		"patch",
 		"resourcequotas",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
-			return true, nil, errors.New("failed to update resource quota")
+			return isApply(action), nil, errors.New("failed to update resource quota")
 		},
 	)
 
@@ -335,8 +786,8 @@ func TestApplyResourceQuotas_FailureUpdate(t *testing.T) {
 }
 
 func TestApplyResourceQuotas_EmptyQuota(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	quota := &domain.Quota{} // 👈 Empty quota should return "QuotaUnchanged"
 
@@ -347,8 +798,8 @@ func TestApplyResourceQuotas_EmptyQuota(t *testing.T) {
 }
 
 func TestApplyResourceQuotas_FailureConvertQuota(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	// Simulate a quota that causes conversion failure
 	quota := &domain.Quota{
@@ -363,15 +814,15 @@ func TestApplyResourceQuotas_FailureConvertQuota(t *testing.T) {
 }
 
 func TestApplyResourceQuotas_LabelOnCreate(t *testing.T) {
-	clientset := fake.NewSimpleClientset()
-	service := NewKubernetesNamespaceService(clientset)
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
 
 	quota := &domain.Quota{MemoryRequest: "10Gi"}
 
-	clientset.PrependReactor("create", "resourcequotas",
+	clientset.PrependReactor("patch", "resourcequotas",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
-			createAction := action.(k8stesting.CreateAction)
-			obj := createAction.GetObject().(*v1.ResourceQuota)
+			var obj v1.ResourceQuota
+			assert.NoError(t, json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &obj))
 
 			labels := obj.GetLabels()
 			assert.Equal(t, "onyxia", labels["created-by"], "Expected label 'created-by: onyxia'")
@@ -385,7 +836,7 @@ func TestApplyResourceQuotas_LabelOnCreate(t *testing.T) {
 }
 
 func TestApplyResourceQuotas_LabelOnUpdate(t *testing.T) {
-	clientset := fake.NewSimpleClientset(&v1.ResourceQuota{
+	clientset := legacyClientset(t, &v1.ResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      QuotaName,
 			Namespace: "test-namespace",
@@ -398,17 +849,20 @@ func TestApplyResourceQuotas_LabelOnUpdate(t *testing.T) {
 		},
 	})
 
-	service := NewKubernetesNamespaceService(clientset)
+	service := newLegacyService(clientset)
 
-	clientset.PrependReactor("update", "resourcequotas",
+	clientset.PrependReactor("patch", "resourcequotas",
 		func(action k8stesting.Action) (bool, runtime.Object, error) {
-			updateAction := action.(k8stesting.UpdateAction)
-			obj := updateAction.GetObject().(*v1.ResourceQuota)
+			if !isApply(action) {
+				return false, nil, nil
+			}
+			var obj v1.ResourceQuota
+			assert.NoError(t, json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &obj))
 
 			labels := obj.GetLabels()
 			assert.Equal(t, "onyxia", labels["created-by"], "Expected label 'created-by: onyxia'")
 
-			return false, nil, nil // let clientset do the actual update
+			return false, nil, nil // let clientset do the actual apply
 		},
 	)
 
@@ -473,3 +927,380 @@ func TestConvertQuotaToResourceMap_InvalidQuantity(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "quantities must match")
 }
+
+func TestConvertQuotaToResourceMap_Resources(t *testing.T) {
+	quota := domain.Quota{
+		CPURequest: "4",
+		Resources: map[string]string{
+			"amd.com/gpu":                                       "1",
+			"nvidia.com/mig-1g.5gb":                             "2",
+			"count/services.loadbalancers":                      "0",
+			"persistentvolumeclaims":                            "10",
+			"gold.storageclass.storage.k8s.io/requests.storage": "500Gi",
+		},
+	}
+
+	result, err := convertQuotaToResourceMap(quota)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 6)
+	assert.True(t, result["amd.com/gpu"].Equal(resource.MustParse("1")))
+	assert.True(t, result["gold.storageclass.storage.k8s.io/requests.storage"].
+		Equal(resource.MustParse("500Gi")))
+}
+
+func TestConvertQuotaToResourceMap_InvalidResourceName(t *testing.T) {
+	_, err := convertQuotaToResourceMap(domain.Quota{
+		Resources: map[string]string{"amd.com/gpu!": "1"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid resource name \"amd.com/gpu!\"")
+}
+
+func TestConvertQuotaToResourceMap_DuplicateResource(t *testing.T) {
+	_, err := convertQuotaToResourceMap(domain.Quota{
+		CPURequest: "4",
+		Resources:  map[string]string{"requests.cpu": "8"},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "resource \"requests.cpu\" is set twice")
+}
+
+// ✅ Test: Changing an open-ended resource updates the quota
+func TestApplyResourceQuotas_ResourcesUpdated(t *testing.T) {
+	existing := &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: QuotaName, Namespace: "test-namespace"},
+		Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{
+			"amd.com/gpu": resource.MustParse("1"),
+		}},
+	}
+	clientset := legacyClientset(t, existing)
+	service := newLegacyService(clientset)
+
+	result, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{Resources: map[string]string{"amd.com/gpu": "2"}},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaUpdated, result)
+}
+
+func gpuScopedQuota(gpus string) domain.ScopedQuota {
+	return domain.ScopedQuota{
+		Name: "gpu-high",
+		ScopeSelector: []domain.ScopeSelectorRequirement{
+			{ScopeName: "PriorityClass", Operator: "In", Values: []string{"high"}},
+		},
+		Hard: map[string]string{"requests.nvidia.com/gpu": gpus},
+	}
+}
+
+// ✅ Test: Scoped Quotas are Created next to the Main Quota
+func TestApplyResourceQuotas_ScopedCreated(t *testing.T) {
+	clientset := legacyClientset(t)
+	service := newLegacyService(clientset)
+
+	quota := &domain.Quota{
+		CPURequest: "4",
+		Scoped: []domain.ScopedQuota{
+			gpuScopedQuota("1"),
+			{
+				Name:   "best-effort",
+				Scopes: []string{"BestEffort"},
+				Hard:   map[string]string{"pods": "5"},
+			},
+		},
+	}
+
+	result, err := service.ApplyResourceQuotas(context.Background(), "test-namespace", quota)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaCreated, result)
+
+	gpu, err := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), "onyxia-quota-gpu-high", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "onyxia", gpu.Labels["created-by"])
+	assert.Equal(t, v1.ScopeSelectorOpIn, gpu.Spec.ScopeSelector.MatchExpressions[0].Operator)
+	assert.True(t, gpu.Spec.Hard["requests.nvidia.com/gpu"].Equal(resource.MustParse("1")))
+
+	bestEffort, err := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), "onyxia-quota-best-effort", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		[]v1.ResourceQuotaScope{v1.ResourceQuotaScopeBestEffort},
+		bestEffort.Spec.Scopes,
+	)
+}
+
+// ✅ Test: Scoped Quota with Different Scopes is Recreated
+func TestApplyResourceQuotas_ScopedScopesChanged(t *testing.T) {
+	clientset := legacyClientset(t, &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "onyxia-quota-gpu-high",
+			Namespace: "test-namespace",
+			Labels:    managedLabels(),
+		},
+		Spec: v1.ResourceQuotaSpec{
+			Scopes: []v1.ResourceQuotaScope{v1.ResourceQuotaScopeTerminating},
+			Hard:   v1.ResourceList{"requests.nvidia.com/gpu": resource.MustParse("1")},
+		},
+	})
+	service := newLegacyService(clientset)
+
+	_, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{Scoped: []domain.ScopedQuota{gpuScopedQuota("1")}},
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(clientset.Actions(), func(a k8stesting.Action) bool {
+		return a.GetVerb() == "delete"
+	}))
+
+	gpu, err := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), "onyxia-quota-gpu-high", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, gpu.Spec.Scopes)
+	assert.NotNil(t, gpu.Spec.ScopeSelector)
+}
+
+// ✅ Test: Annotations of a Recreated Quota are Kept
+func TestApplyResourceQuotas_ScopedScopesChangedKeepsAnnotations(t *testing.T) {
+	clientset := legacyClientset(t, &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "onyxia-quota-gpu-high",
+			Namespace:   "test-namespace",
+			Labels:      managedLabels(),
+			Annotations: map[string]string{IgnoreKeysAnnotation: "limits.nvidia.com/gpu"},
+		},
+		Spec: v1.ResourceQuotaSpec{
+			Scopes: []v1.ResourceQuotaScope{v1.ResourceQuotaScopeTerminating},
+			Hard:   v1.ResourceList{"requests.nvidia.com/gpu": resource.MustParse("1")},
+		},
+	})
+	service := newLegacyService(clientset)
+
+	_, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{Scoped: []domain.ScopedQuota{gpuScopedQuota("2")}},
+	)
+
+	assert.NoError(t, err)
+
+	gpu, err := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), "onyxia-quota-gpu-high", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "limits.nvidia.com/gpu", gpu.Annotations[IgnoreKeysAnnotation])
+	assert.True(t, gpu.Spec.Hard["requests.nvidia.com/gpu"].Equal(resource.MustParse("2")))
+}
+
+// ✅ Test: Scoped Quotas Removed from the Profile are Deleted
+func TestApplyResourceQuotas_ScopedPruned(t *testing.T) {
+	stale := func(name string, annotations map[string]string) *v1.ResourceQuota {
+		return &v1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "test-namespace",
+			Labels:      managedLabels(),
+			Annotations: annotations,
+		}}
+	}
+	clientset := legacyClientset(t,
+		stale("onyxia-quota-old", nil),
+		stale("onyxia-quota-kept", map[string]string{IgnoreQuotaAnnotation: "true"}),
+		stale("other-quota", nil),
+	)
+	service := newLegacyService(clientset)
+
+	_, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{CPURequest: "4"},
+	)
+
+	assert.NoError(t, err)
+
+	quotas, _ := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		List(context.Background(), metav1.ListOptions{})
+	var names []string
+	for _, quota := range quotas.Items {
+		names = append(names, quota.Name)
+	}
+	assert.ElementsMatch(t, []string{QuotaName, "onyxia-quota-kept", "other-quota"}, names)
+}
+
+func TestValidateQuota_Scoped(t *testing.T) {
+	assert.NoError(t, ValidateQuota(domain.Quota{
+		Scoped: []domain.ScopedQuota{gpuScopedQuota("1")},
+	}))
+
+	invalidScope := gpuScopedQuota("1")
+	invalidScope.Scopes = []string{"Expensive"}
+	assert.ErrorContains(t, ValidateQuota(domain.Quota{
+		Scoped: []domain.ScopedQuota{invalidScope},
+	}), "invalid scope \"Expensive\"")
+
+	assert.ErrorContains(t, ValidateQuota(domain.Quota{
+		Scoped: []domain.ScopedQuota{gpuScopedQuota("1"), gpuScopedQuota("2")},
+	}), "defined twice")
+
+	assert.ErrorContains(t, ValidateQuota(domain.Quota{
+		Scoped: []domain.ScopedQuota{{Name: "empty"}},
+	}), "has no resource")
+}
+
+// ✅ Test: Resources Listed in the Ignore-Keys Annotation are Kept
+func TestApplyResourceQuotas_PartiallyIgnored(t *testing.T) {
+	clientset := legacyClientset(t, &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QuotaName,
+			Namespace: "test-namespace",
+			Annotations: map[string]string{
+				IgnoreKeysAnnotation: "limits.nvidia.com/gpu, requests.storage",
+			},
+		},
+		Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{
+			v1.ResourceRequestsCPU:    resource.MustParse("2"),
+			"limits.nvidia.com/gpu":   resource.MustParse("4"), // 👈 raised by an admin
+			v1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+		}},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{
+			CPURequest:     "4",
+			GPULimit:       "1",
+			StorageRequest: "100Gi",
+			MemoryRequest:  "8Gi",
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaPartiallyIgnored, result)
+
+	quota, _ := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		Get(context.Background(), QuotaName, metav1.GetOptions{})
+	assert.Len(t, quota.Spec.Hard, 3)
+	assert.True(t, quota.Spec.Hard[v1.ResourceRequestsCPU].Equal(resource.MustParse("4")))
+	assert.True(t, quota.Spec.Hard["limits.nvidia.com/gpu"].Equal(resource.MustParse("4")))
+	// ✅ An ignored resource that is not set stays unset
+	assert.NotContains(t, quota.Spec.Hard, v1.ResourceRequestsStorage)
+}
+
+// ✅ Test: Ignore-Keys Annotation with Nothing Else to Reconcile
+func TestApplyResourceQuotas_PartiallyIgnoredUnchanged(t *testing.T) {
+	clientset := legacyClientset(t, &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        QuotaName,
+			Namespace:   "test-namespace",
+			Annotations: map[string]string{IgnoreKeysAnnotation: "requests.cpu"},
+		},
+		Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{
+			v1.ResourceRequestsCPU:    resource.MustParse("8"),
+			v1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+		}},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.ApplyResourceQuotas(
+		context.Background(),
+		"test-namespace",
+		&domain.Quota{CPURequest: "4", MemoryRequest: "8Gi"},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaPartiallyIgnored, result)
+	assert.False(t, slices.ContainsFunc(clientset.Actions(), func(a k8stesting.Action) bool {
+		return a.GetVerb() == "patch"
+	}))
+}
+
+// ✅ Test: Managed Quota Deleted
+func TestDeleteResourceQuota(t *testing.T) {
+	clientset := legacyClientset(t, &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QuotaName,
+			Namespace: "test-namespace",
+			Labels:    managedLabels(),
+		},
+	})
+	service := newLegacyService(clientset)
+
+	result, err := service.DeleteResourceQuota(context.Background(), "test-namespace")
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaDeleted, result)
+
+	quotas, _ := clientset.CoreV1().
+		ResourceQuotas("test-namespace").
+		List(context.Background(), metav1.ListOptions{})
+	assert.Empty(t, quotas.Items)
+}
+
+// ✅ Test: Quota Kept When Ignored or Not Managed
+func TestDeleteResourceQuota_Kept(t *testing.T) {
+	tests := []struct {
+		name     string
+		quota    metav1.ObjectMeta
+		expected interfaces.QuotaApplicationResult
+	}{
+		{
+			name: "ignored",
+			quota: metav1.ObjectMeta{
+				Labels:      managedLabels(),
+				Annotations: map[string]string{IgnoreQuotaAnnotation: "true"},
+			},
+			expected: interfaces.QuotaIgnored,
+		},
+		{
+			name:     "not managed",
+			quota:    metav1.ObjectMeta{Labels: map[string]string{"created-by": "admin"}},
+			expected: interfaces.QuotaUnchanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.quota.Name = QuotaName
+			tt.quota.Namespace = "test-namespace"
+			clientset := legacyClientset(t, &v1.ResourceQuota{ObjectMeta: tt.quota})
+			service := newLegacyService(clientset)
+
+			result, err := service.DeleteResourceQuota(context.Background(), "test-namespace")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+
+			_, err = clientset.CoreV1().
+				ResourceQuotas("test-namespace").
+				Get(context.Background(), QuotaName, metav1.GetOptions{})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// ✅ Test: No Quota to Delete
+func TestDeleteResourceQuota_NotFound(t *testing.T) {
+	service := NewKubernetesNamespaceService(fake.NewClientset())
+
+	result, err := service.DeleteResourceQuota(context.Background(), "test-namespace")
+
+	assert.NoError(t, err)
+	assert.Equal(t, interfaces.QuotaUnchanged, result)
+}
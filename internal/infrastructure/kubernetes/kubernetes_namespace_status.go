@@ -0,0 +1,86 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetNamespaceStatus returns the namespace and its quota, provided the namespace is owned by the
+// onboarding for identity, see checkOwnership.
+func (s *KubernetesNamespaceService) GetNamespaceStatus(
+	ctx context.Context,
+	name string,
+	identity string,
+) (domain.NamespaceStatus, error) {
+	status := domain.NamespaceStatus{Name: name}
+
+	namespace, err := s.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	owned, err := checkOwnership(namespace, identity)
+	if err != nil {
+		return status, err
+	}
+	if !owned {
+		return status, fmt.Errorf(
+			"%w: namespace %s was not created by onboarding",
+			domain.ErrNamespaceNotOwned,
+			name,
+		)
+	}
+
+	status.Exists = true
+	status.Annotations = namespace.Annotations
+	status.Labels = namespace.Labels
+
+	quota, err := s.clientset.CoreV1().
+		ResourceQuotas(name).
+		Get(ctx, QuotaName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("failed to get resource quota: %w", err)
+	}
+
+	status.Quota = &domain.QuotaStatus{
+		Hard:    formatResourceList(quota.Spec.Hard),
+		Used:    formatResourceList(quota.Status.Used),
+		Ignored: quota.Annotations[IgnoreQuotaAnnotation] == "true",
+	}
+
+	return status, nil
+}
+
+// GetNamespaceAnnotations returns the annotations of a namespace, or nil when it does not exist.
+func (s *KubernetesNamespaceService) GetNamespaceAnnotations(
+	ctx context.Context,
+	name string,
+) (map[string]string, error) {
+	namespace, err := s.getNamespace(ctx, name)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	return namespace.Annotations, nil
+}
+
+func formatResourceList(resources v1.ResourceList) map[string]string {
+	result := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// ✅ Test: Namespace and Quota Exist
+func TestGetNamespaceStatus_WithQuota(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-test",
+			Annotations: map[string]string{"onyxia.sh/identity": "user:test"},
+			Labels:      map[string]string{"created-by": "onyxia"},
+		}},
+		&v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        QuotaName,
+				Namespace:   "user-test",
+				Annotations: map[string]string{IgnoreQuotaAnnotation: "true"},
+			},
+			Spec: v1.ResourceQuotaSpec{Hard: v1.ResourceList{
+				v1.ResourceRequestsCPU: resource.MustParse("4"),
+			}},
+			Status: v1.ResourceQuotaStatus{Used: v1.ResourceList{
+				v1.ResourceRequestsCPU: resource.MustParse("1500m"),
+			}},
+		},
+	)
+	service := &KubernetesNamespaceService{clientset: clientset}
+
+	status, err := service.GetNamespaceStatus(context.Background(), "user-test", "user:test")
+
+	assert.NoError(t, err)
+	assert.True(t, status.Exists)
+	assert.Equal(t, "user:test", status.Annotations["onyxia.sh/identity"])
+	assert.Equal(t, "onyxia", status.Labels["created-by"])
+	assert.Equal(t, map[string]string{"requests.cpu": "4"}, status.Quota.Hard)
+	assert.Equal(t, map[string]string{"requests.cpu": "1500m"}, status.Quota.Used)
+	assert.True(t, status.Quota.Ignored)
+}
+
+// ✅ Test: Namespace Exists Without Quota
+func TestGetNamespaceStatus_WithoutQuota(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "user-test", Labels: managedLabels()}},
+	)
+	service := &KubernetesNamespaceService{clientset: clientset}
+
+	status, err := service.GetNamespaceStatus(context.Background(), "user-test", "user:test")
+
+	assert.NoError(t, err)
+	assert.True(t, status.Exists)
+	assert.Nil(t, status.Quota)
+}
+
+// ❌ Test: Namespaces Not Created by Onboarding, or Belonging to Another Identity, are Refused
+func TestGetNamespaceStatus_NotOwned(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "user-test"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-other",
+			Labels:      managedLabels(),
+			Annotations: map[string]string{domain.IdentityAnnotation: "user:other"},
+		}},
+	)
+	service := &KubernetesNamespaceService{clientset: clientset}
+
+	_, err := service.GetNamespaceStatus(context.Background(), "user-test", "user:test")
+
+	assert.ErrorIs(t, err, domain.ErrNamespaceNotOwned)
+
+	_, err = service.GetNamespaceStatus(context.Background(), "user-other", "user:test")
+
+	assert.ErrorIs(t, err, domain.ErrNamespaceCollision)
+}
+
+// ✅ Test: Namespace Does Not Exist
+func TestGetNamespaceStatus_NotFound(t *testing.T) {
+	service := &KubernetesNamespaceService{clientset: fake.NewSimpleClientset()}
+
+	status, err := service.GetNamespaceStatus(context.Background(), "user-test", "user:test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-test", status.Name)
+	assert.False(t, status.Exists)
+}
+
+// ❌ Test: Failure When Getting the Namespace
+func TestGetNamespaceStatus_Failure(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("get", "namespaces",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("API server unavailable")
+		})
+	service := &KubernetesNamespaceService{clientset: clientset}
+
+	_, err := service.GetNamespaceStatus(context.Background(), "user-test", "user:test")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get namespace")
+}
+
+// ✅ Test: Annotations of a Namespace, Nil When It Does Not Exist
+func TestGetNamespaceAnnotations(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "user-test",
+			Annotations: map[string]string{"onyxia.sh/fingerprint": "abc"},
+		}},
+	)
+	service := &KubernetesNamespaceService{clientset: clientset}
+
+	annotations, err := service.GetNamespaceAnnotations(context.Background(), "user-test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"onyxia.sh/fingerprint": "abc"}, annotations)
+
+	annotations, err = service.GetNamespaceAnnotations(context.Background(), "user-other")
+
+	assert.NoError(t, err)
+	assert.Nil(t, annotations)
+}
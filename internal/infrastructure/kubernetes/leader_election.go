@@ -0,0 +1,93 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// RunWithLeaderElection runs fn while this replica holds the given Lease, so that only one
+// replica of the service runs it at a time. It blocks until ctx is cancelled.
+func RunWithLeaderElection(
+	ctx context.Context,
+	clientset k8s.Interface,
+	leaseNamespace string,
+	leaseName string,
+	fn func(ctx context.Context),
+) error {
+	if leaseNamespace == "" {
+		leaseNamespace = currentNamespace()
+	}
+
+	identity, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		leaseNamespace,
+		leaseName,
+		clientset.CoreV1(),
+		clientset.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create lease lock: %w", err)
+	}
+
+	config := leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+		ReleaseOnCancel: true,
+		Name:            leaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				slog.InfoContext(ctx, "✅ Acquired leadership",
+					slog.String("lease", leaseNamespace+"/"+leaseName),
+					slog.String("identity", identity),
+				)
+				fn(ctx)
+			},
+			OnStoppedLeading: func() {
+				slog.Warn("⚠️ Lost leadership",
+					slog.String("lease", leaseNamespace+"/"+leaseName),
+					slog.String("identity", identity),
+				)
+			},
+		},
+	}
+
+	// 🔹 Run returns when leadership is lost, try to acquire it again until ctx is cancelled
+	for ctx.Err() == nil {
+		elector, err := leaderelection.NewLeaderElector(config)
+		if err != nil {
+			return fmt.Errorf("failed to create leader elector: %w", err)
+		}
+		elector.Run(ctx)
+	}
+
+	return nil
+}
+
+// currentNamespace returns the namespace the service runs in.
+func currentNamespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	return "default"
+}
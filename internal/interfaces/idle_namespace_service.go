@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+)
+
+type IdleNamespaceService interface {
+	// ListIdleNamespaces returns the onboarded namespaces matching selector that carry a last login
+	// annotation or are archived, except ignored ones.
+	ListIdleNamespaces(
+		ctx context.Context,
+		selector map[string]string,
+	) ([]domain.IdleNamespace, error)
+	WarnNamespace(ctx context.Context, namespace string) error
+	ScaleDownNamespace(ctx context.Context, namespace string) (int, error)
+}
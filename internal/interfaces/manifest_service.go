@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+)
+
+type ManifestApplicationResult string
+
+const (
+	ManifestCreated   ManifestApplicationResult = "created"
+	ManifestUpdated   ManifestApplicationResult = "updated"
+	ManifestUnchanged ManifestApplicationResult = "unchanged"
+	ManifestIgnored   ManifestApplicationResult = "ignored"
+)
+
+type AppliedManifestObject struct {
+	Object domain.ManifestObject
+	Result ManifestApplicationResult
+}
+
+type ManifestService interface {
+	ApplyManifest(
+		ctx context.Context,
+		namespace string,
+		manifest string,
+	) ([]AppliedManifestObject, error)
+	PruneManifests(
+		ctx context.Context,
+		namespace string,
+		keep []domain.ManifestObject,
+	) (int, error)
+}
@@ -2,17 +2,23 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 )
 
 type NamespaceCreationResult string
 type QuotaApplicationResult string
+type NamespaceOffboardingResult string
+type RoleBindingApplicationResult string
+type NetworkPolicyApplicationResult string
+type LimitRangeApplicationResult string
 
 const (
 	NamespaceCreated            NamespaceCreationResult = "created"
 	NamespaceAlreadyExists      NamespaceCreationResult = "already_exists"
 	NamespaceAnnotationsUpdated NamespaceCreationResult = "annotations_updated"
+	NamespaceNotOwned           NamespaceCreationResult = "not_owned"
 )
 
 const (
@@ -20,6 +26,38 @@ const (
 	QuotaUpdated   QuotaApplicationResult = "updated"
 	QuotaUnchanged QuotaApplicationResult = "unchanged"
 	QuotaIgnored   QuotaApplicationResult = "ignored"
+	QuotaDeleted   QuotaApplicationResult = "deleted"
+
+	QuotaPartiallyIgnored QuotaApplicationResult = "partially_ignored"
+)
+
+const (
+	NamespaceDeleted         NamespaceOffboardingResult = "deleted"
+	NamespaceArchived        NamespaceOffboardingResult = "archived"
+	NamespaceAlreadyArchived NamespaceOffboardingResult = "already_archived"
+	NamespaceNotFound        NamespaceOffboardingResult = "not_found"
+)
+
+const (
+	RoleBindingCreated   RoleBindingApplicationResult = "created"
+	RoleBindingUpdated   RoleBindingApplicationResult = "updated"
+	RoleBindingUnchanged RoleBindingApplicationResult = "unchanged"
+	RoleBindingIgnored   RoleBindingApplicationResult = "ignored"
+)
+
+const (
+	NetworkPolicyCreated   NetworkPolicyApplicationResult = "created"
+	NetworkPolicyUpdated   NetworkPolicyApplicationResult = "updated"
+	NetworkPolicyUnchanged NetworkPolicyApplicationResult = "unchanged"
+	NetworkPolicyIgnored   NetworkPolicyApplicationResult = "ignored"
+)
+
+const (
+	LimitRangeCreated   LimitRangeApplicationResult = "created"
+	LimitRangeUpdated   LimitRangeApplicationResult = "updated"
+	LimitRangeUnchanged LimitRangeApplicationResult = "unchanged"
+	LimitRangeIgnored   LimitRangeApplicationResult = "ignored"
+	LimitRangeDeleted   LimitRangeApplicationResult = "deleted"
 )
 
 type NamespaceService interface {
@@ -29,9 +67,50 @@ type NamespaceService interface {
 		annotations map[string]string,
 		labels map[string]string,
 	) (NamespaceCreationResult, error)
+	GetNamespaceStatus(
+		ctx context.Context,
+		name string,
+		identity string,
+	) (domain.NamespaceStatus, error)
+	GetNamespaceAnnotations(ctx context.Context, name string) (map[string]string, error)
+	ListNamespaces(
+		ctx context.Context,
+		selector map[string]string,
+	) ([]domain.ManagedNamespace, error)
 	ApplyResourceQuotas(
 		ctx context.Context,
 		namespace string,
 		quota *domain.Quota,
 	) (QuotaApplicationResult, error)
+	DeleteResourceQuota(ctx context.Context, namespace string) (QuotaApplicationResult, error)
+	OffboardNamespace(
+		ctx context.Context,
+		name string,
+		identity string,
+		mode domain.OffboardingMode,
+		gracePeriod time.Duration,
+	) (NamespaceOffboardingResult, error)
+	ApplyRoleBinding(
+		ctx context.Context,
+		namespace string,
+		roleBinding *domain.RoleBinding,
+	) (RoleBindingApplicationResult, error)
+	PruneRoleBindings(
+		ctx context.Context,
+		namespace string,
+		label string,
+		seenBefore time.Time,
+	) (int, error)
+	ApplyNetworkPolicy(
+		ctx context.Context,
+		namespace string,
+		policy *domain.NetworkPolicy,
+	) (NetworkPolicyApplicationResult, error)
+	PruneNetworkPolicies(ctx context.Context, namespace string, keep []string) (int, error)
+	ApplyLimitRange(
+		ctx context.Context,
+		namespace string,
+		limitRange *domain.LimitRange,
+	) (LimitRangeApplicationResult, error)
+	DeleteLimitRange(ctx context.Context, namespace string) (LimitRangeApplicationResult, error)
 }
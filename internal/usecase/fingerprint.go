@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+)
+
+// desiredState is the state of a namespace hashed into its fingerprint. Maps are encoded with
+// sorted keys, so equal states have equal fingerprints.
+type desiredState struct {
+	Annotations  map[string]string `json:"annotations"`
+	Labels       map[string]string `json:"labels"`
+	Quota        *domain.Quota     `json:"quota"`
+	QuotaProfile string            `json:"quotaProfile"`
+	Caller       caller            `json:"caller"`
+	Config       config            `json:"config"`
+}
+
+// caller is who onboards the namespace: the RBAC bindings, e.g. of group members, and the
+// manifests depend on them.
+type caller struct {
+	UserName string   `json:"userName"`
+	Roles    []string `json:"roles"`
+	Groups   []string `json:"groups"`
+}
+
+// config is the configuration of the content of namespaces, so that changing it onboards them
+// again. Manifests are hashed by the source of their templates.
+type config struct {
+	RBAC            domain.RBAC            `json:"rbac"`
+	NetworkPolicies domain.NetworkPolicies `json:"networkPolicies"`
+	Manifests       map[string]string      `json:"manifests"`
+	Region          string                 `json:"region"`
+}
+
+// fingerprint returns the hash of the state an onboarding applies to a namespace, along with the
+// selected quota profile. The last login is left out, as it changes on every onboarding.
+func (s *onboardingUsecase) fingerprint(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+	namespace string,
+	annotations map[string]string,
+	labels map[string]string,
+) (string, string, error) {
+	state := desiredState{
+		Annotations: maps.Clone(annotations),
+		Labels:      labels,
+		Caller: caller{
+			UserName: req.UserName,
+			Roles:    sorted(req.UserRoles),
+			Groups:   sorted(req.UserGroups),
+		},
+		Config: s.config(),
+	}
+	delete(state.Annotations, domain.LastLoginAnnotation)
+	delete(state.Annotations, domain.FingerprintAnnotation)
+
+	if s.quotas.Enabled {
+		var err error
+		state.Quota, state.QuotaProfile, err = s.getQuota(ctx, req, namespace)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode namespace state: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), state.QuotaProfile, nil
+}
+
+func (s *onboardingUsecase) config() config {
+	manifests := make(map[string]string)
+	if s.manifests.Enabled {
+		for _, manifest := range s.manifests.Templates {
+			for _, template := range manifest.Template.Templates() {
+				if template.Tree != nil && template.Root != nil {
+					manifests[manifest.Name+"/"+template.Name()] = template.Root.String()
+				}
+			}
+		}
+	}
+
+	return config{
+		RBAC:            s.rbac,
+		NetworkPolicies: s.networkPolicies,
+		Manifests:       manifests,
+		Region:          s.manifests.Region,
+	}
+}
+
+func sorted(values []string) []string {
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return values
+}
+
+// onboardedRecently tells whether the namespace was onboarded with the given fingerprint and its
+// owner logged in less than SkipIfOnboardedWithin ago, and was not archived since. Any failure to
+// tell means it was not.
+func (s *onboardingUsecase) onboardedRecently(
+	ctx context.Context,
+	namespace string,
+	fingerprint string,
+) bool {
+	annotations, err := s.namespaceService.GetNamespaceAnnotations(ctx, namespace)
+	if err != nil {
+		slog.WarnContext(ctx, "⚠️ Failed to get namespace annotations, not skipping onboarding",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return false
+	}
+
+	// 🔹 An archived namespace is restored by the onboarding, whatever its fingerprint
+	if annotations[domain.ArchivedAtAnnotation] != "" ||
+		annotations[domain.FingerprintAnnotation] != fingerprint {
+		return false
+	}
+
+	millis, err := strconv.ParseInt(annotations[domain.LastLoginAnnotation], 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return time.Since(time.UnixMilli(millis)) < s.namespace.SkipIfOnboardedWithin
+}
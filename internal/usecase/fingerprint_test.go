@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupSkippingUsecase(
+	mockService *MockNamespaceService,
+	quotas domain.Quotas,
+) *onboardingUsecase {
+	usecase := setupPrivateUsecase(mockService, quotas)
+	usecase.namespace.Annotation.Enabled = true
+	usecase.namespace.Annotation.Dynamic.LastLoginTimestamp = true
+	usecase.namespace.SkipIfOnboardedWithin = time.Hour
+	return usecase
+}
+
+// ✅ Test the fingerprint ignores the last login and changes with the quota
+func TestFingerprint(t *testing.T) {
+	usecase := setupPrivateUsecase(nil, domain.Quotas{
+		Enabled: true,
+		Default: domain.Quota{CPURequest: "1"},
+	})
+	req := domain.OnboardingRequest{UserName: testUserName}
+	labels := map[string]string{"created-by": "onyxia"}
+
+	fingerprint, profile, err := usecase.fingerprint(context.Background(), req, userNamespace,
+		map[string]string{domain.LastLoginAnnotation: "1"}, labels)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", profile)
+
+	other, _, err := usecase.fingerprint(context.Background(), req, userNamespace,
+		map[string]string{domain.LastLoginAnnotation: "2"}, labels)
+	assert.NoError(t, err)
+	assert.Equal(t, fingerprint, other, "Expected the last login to be ignored")
+
+	usecase.quotas.Default.CPURequest = "2"
+	other, _, err = usecase.fingerprint(context.Background(), req, userNamespace,
+		map[string]string{domain.LastLoginAnnotation: "1"}, labels)
+	assert.NoError(t, err)
+	assert.NotEqual(t, fingerprint, other, "Expected a new quota to change the fingerprint")
+}
+
+// ✅ Test the fingerprint changes with the caller and the configuration of namespace content
+func TestFingerprint_CallerAndConfig(t *testing.T) {
+	groupName := testGroupName
+	req := domain.OnboardingRequest{Group: &groupName, UserName: "alice"}
+	annotations := map[string]string{domain.IdentityAnnotation: "group:" + testGroupName}
+
+	fingerprint := func(usecase *onboardingUsecase, req domain.OnboardingRequest) string {
+		fingerprint, _, err := usecase.fingerprint(
+			context.Background(), req, groupNamespace, annotations, nil,
+		)
+		assert.NoError(t, err)
+		return fingerprint
+	}
+
+	usecase := setupPrivateUsecase(nil, domain.Quotas{})
+	base := fingerprint(usecase, req)
+
+	tests := map[string]func(*onboardingUsecase, *domain.OnboardingRequest){
+		"other member": func(_ *onboardingUsecase, req *domain.OnboardingRequest) {
+			req.UserName = "bob"
+		},
+		"other roles": func(_ *onboardingUsecase, req *domain.OnboardingRequest) {
+			req.UserRoles = []string{"admin"}
+		},
+		"other groups": func(_ *onboardingUsecase, req *domain.OnboardingRequest) {
+			req.UserGroups = []string{testGroupName}
+		},
+		"rbac": func(usecase *onboardingUsecase, _ *domain.OnboardingRequest) {
+			usecase.rbac = domain.RBAC{Enabled: true, ClusterRole: "admin"}
+		},
+		"network policies": func(usecase *onboardingUsecase, _ *domain.OnboardingRequest) {
+			usecase.networkPolicies = domain.NetworkPolicies{
+				Enabled: true,
+				Group:   []domain.NetworkPolicy{{Name: "deny-all", Spec: "podSelector: {}"}},
+			}
+		},
+		"manifests": func(usecase *onboardingUsecase, _ *domain.OnboardingRequest) {
+			usecase.manifests = domain.Manifests{
+				Enabled: true,
+				Templates: []domain.Manifest{{
+					Name:     "config",
+					Template: template.Must(template.New("config").Parse("{{ .Namespace }}")),
+				}},
+			}
+		},
+	}
+
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			usecase := setupPrivateUsecase(nil, domain.Quotas{})
+			req := req
+			change(usecase, &req)
+
+			assert.NotEqual(t, base, fingerprint(usecase, req))
+		})
+	}
+}
+
+// ✅ Test `Onboard` skips a namespace onboarded recently with the same state
+func Test_Onboard_SkipsRecentOnboarding(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{Enabled: true, Default: domain.Quota{CPURequest: "1"}}
+	usecase := setupSkippingUsecase(mockService, quotas)
+
+	mockService.On("GetNamespaceAnnotations", mock.Anything, userNamespace).
+		Return(nil, nil).
+		Once()
+	mockService.On("CreateNamespace", mock.Anything, userNamespace).
+		Return(interfaces.NamespaceCreated, nil).
+		Once()
+	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
+		Return(interfaces.QuotaCreated, nil).
+		Once()
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil).
+		Once()
+
+	req := domain.OnboardingRequest{UserName: testUserName}
+	result, err := usecase.Onboard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Skipped)
+	assert.NotEmpty(t, result.Annotations[domain.FingerprintAnnotation])
+
+	mockService.On("GetNamespaceAnnotations", mock.Anything, userNamespace).
+		Return(result.Annotations, nil)
+
+	result, err = usecase.Onboard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	assert.Equal(t, "default", result.QuotaProfile)
+	mockService.AssertExpectations(t)
+}
+
+// ✅ Test `Onboard` only skips unchanged namespaces whose owner logged in recently
+func Test_Onboard_SkipIfOnboardedWithin(t *testing.T) {
+	quotas := domain.Quotas{Enabled: true, Default: domain.Quota{CPURequest: "1"}}
+	req := domain.OnboardingRequest{UserName: testUserName}
+
+	recent := fmt.Sprint(time.Now().Add(-time.Minute).UnixMilli())
+	old := fmt.Sprint(time.Now().Add(-2 * time.Hour).UnixMilli())
+
+	fingerprint, _, err := setupSkippingUsecase(nil, quotas).fingerprint(
+		context.Background(), req, userNamespace,
+		map[string]string{
+			domain.IdentityAnnotation: "user:" + testUserName,
+			domain.RolesAnnotation:    "",
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		annotations map[string]string
+		skipped     bool
+	}{
+		"unchanged": {
+			annotations: map[string]string{
+				domain.FingerprintAnnotation: fingerprint,
+				domain.LastLoginAnnotation:   recent,
+			},
+			skipped: true,
+		},
+		"state changed": {
+			annotations: map[string]string{
+				domain.FingerprintAnnotation: "other",
+				domain.LastLoginAnnotation:   recent,
+			},
+		},
+		"login too old": {
+			annotations: map[string]string{
+				domain.FingerprintAnnotation: fingerprint,
+				domain.LastLoginAnnotation:   old,
+			},
+		},
+		// 🔹 Offboarded then onboarded again right away: the archive must be restored
+		"archived": {
+			annotations: map[string]string{
+				domain.FingerprintAnnotation: fingerprint,
+				domain.LastLoginAnnotation:   recent,
+				domain.ArchivedAtAnnotation:  time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(MockNamespaceService)
+			usecase := setupSkippingUsecase(mockService, quotas)
+
+			mockService.On("GetNamespaceAnnotations", mock.Anything, userNamespace).
+				Return(tt.annotations, nil)
+			if !tt.skipped {
+				mockService.On("CreateNamespace", mock.Anything, userNamespace).
+					Return(interfaces.NamespaceAnnotationsUpdated, nil)
+				mockService.On(
+					"ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default,
+				).Return(interfaces.QuotaUnchanged, nil)
+				mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+					Return(interfaces.LimitRangeUnchanged, nil)
+			}
+
+			result, err := usecase.Onboard(context.Background(), req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.skipped, result.Skipped)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
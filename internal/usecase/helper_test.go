@@ -2,11 +2,13 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	usercontext "github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/context"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/singleflight"
 )
 
 // ✅ Shared Test Constants
@@ -37,6 +39,32 @@ func (m *MockNamespaceService) CreateNamespace(
 	return args.Get(0).(interfaces.NamespaceCreationResult), args.Error(1)
 }
 
+func (m *MockNamespaceService) GetNamespaceStatus(
+	ctx context.Context,
+	name string,
+	identity string,
+) (domain.NamespaceStatus, error) {
+	args := m.Called(ctx, name, identity)
+	return args.Get(0).(domain.NamespaceStatus), args.Error(1)
+}
+
+func (m *MockNamespaceService) GetNamespaceAnnotations(
+	ctx context.Context,
+	name string,
+) (map[string]string, error) {
+	args := m.Called(ctx, name)
+	annotations, _ := args.Get(0).(map[string]string)
+	return annotations, args.Error(1)
+}
+
+func (m *MockNamespaceService) ListNamespaces(
+	ctx context.Context,
+	selector map[string]string,
+) ([]domain.ManagedNamespace, error) {
+	args := m.Called(ctx, selector)
+	return args.Get(0).([]domain.ManagedNamespace), args.Error(1)
+}
+
 func (m *MockNamespaceService) ApplyResourceQuotas(
 	ctx context.Context,
 	namespace string,
@@ -46,6 +74,132 @@ func (m *MockNamespaceService) ApplyResourceQuotas(
 	return args.Get(0).(interfaces.QuotaApplicationResult), args.Error(1)
 }
 
+func (m *MockNamespaceService) DeleteResourceQuota(
+	ctx context.Context,
+	namespace string,
+) (interfaces.QuotaApplicationResult, error) {
+	args := m.Called(ctx, namespace)
+	return args.Get(0).(interfaces.QuotaApplicationResult), args.Error(1)
+}
+
+func (m *MockNamespaceService) OffboardNamespace(
+	ctx context.Context,
+	name string,
+	identity string,
+	mode domain.OffboardingMode,
+	gracePeriod time.Duration,
+) (interfaces.NamespaceOffboardingResult, error) {
+	args := m.Called(ctx, name, identity, mode, gracePeriod)
+	return args.Get(0).(interfaces.NamespaceOffboardingResult), args.Error(1)
+}
+
+func (m *MockNamespaceService) ApplyRoleBinding(
+	ctx context.Context,
+	namespace string,
+	roleBinding *domain.RoleBinding,
+) (interfaces.RoleBindingApplicationResult, error) {
+	args := m.Called(ctx, namespace, roleBinding)
+	return args.Get(0).(interfaces.RoleBindingApplicationResult), args.Error(1)
+}
+
+func (m *MockNamespaceService) PruneRoleBindings(
+	ctx context.Context,
+	namespace string,
+	label string,
+	seenBefore time.Time,
+) (int, error) {
+	args := m.Called(ctx, namespace, label, seenBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNamespaceService) ApplyNetworkPolicy(
+	ctx context.Context,
+	namespace string,
+	policy *domain.NetworkPolicy,
+) (interfaces.NetworkPolicyApplicationResult, error) {
+	args := m.Called(ctx, namespace, policy)
+	return args.Get(0).(interfaces.NetworkPolicyApplicationResult), args.Error(1)
+}
+
+func (m *MockNamespaceService) PruneNetworkPolicies(
+	ctx context.Context,
+	namespace string,
+	keep []string,
+) (int, error) {
+	args := m.Called(ctx, namespace, keep)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNamespaceService) ApplyLimitRange(
+	ctx context.Context,
+	namespace string,
+	limitRange *domain.LimitRange,
+) (interfaces.LimitRangeApplicationResult, error) {
+	args := m.Called(ctx, namespace, limitRange)
+	return args.Get(0).(interfaces.LimitRangeApplicationResult), args.Error(1)
+}
+
+func (m *MockNamespaceService) DeleteLimitRange(
+	ctx context.Context,
+	namespace string,
+) (interfaces.LimitRangeApplicationResult, error) {
+	args := m.Called(ctx, namespace)
+	return args.Get(0).(interfaces.LimitRangeApplicationResult), args.Error(1)
+}
+
+// ✅ Mock `ManifestService`
+type MockManifestService struct {
+	mock.Mock
+}
+
+var _ interfaces.ManifestService = (*MockManifestService)(nil)
+
+func (m *MockManifestService) ApplyManifest(
+	ctx context.Context,
+	namespace string,
+	manifest string,
+) ([]interfaces.AppliedManifestObject, error) {
+	args := m.Called(ctx, namespace, manifest)
+	return args.Get(0).([]interfaces.AppliedManifestObject), args.Error(1)
+}
+
+func (m *MockManifestService) PruneManifests(
+	ctx context.Context,
+	namespace string,
+	keep []domain.ManifestObject,
+) (int, error) {
+	args := m.Called(ctx, namespace, keep)
+	return args.Int(0), args.Error(1)
+}
+
+// ✅ Mock `IdleNamespaceService`
+type MockIdleNamespaceService struct {
+	mock.Mock
+}
+
+var _ interfaces.IdleNamespaceService = (*MockIdleNamespaceService)(nil)
+
+func (m *MockIdleNamespaceService) ListIdleNamespaces(
+	ctx context.Context,
+	selector map[string]string,
+) ([]domain.IdleNamespace, error) {
+	args := m.Called(ctx, selector)
+	return args.Get(0).([]domain.IdleNamespace), args.Error(1)
+}
+
+func (m *MockIdleNamespaceService) WarnNamespace(ctx context.Context, namespace string) error {
+	args := m.Called(ctx, namespace)
+	return args.Error(0)
+}
+
+func (m *MockIdleNamespaceService) ScaleDownNamespace(
+	ctx context.Context,
+	namespace string,
+) (int, error) {
+	args := m.Called(ctx, namespace)
+	return args.Int(0), args.Error(1)
+}
+
 var mockUserContextReader, _ = usercontext.NewFakeUserContext(&domain.User{
 	Username: testUserName,
 	Groups:   []string{testGroupName},
@@ -61,6 +215,7 @@ func setupUsecase(
 ) domain.OnboardingUsecase {
 	return NewOnboardingUsecase(
 		mockService,
+		new(MockManifestService),
 		domain.Namespace{
 			NamespacePrefix:      namespacePrefix,
 			GroupNamespacePrefix: groupNamespacePref,
@@ -71,6 +226,11 @@ func setupUsecase(
 			},
 		},
 		quotas,
+		domain.Offboarding{},
+		domain.RBAC{},
+		domain.NetworkPolicies{},
+		domain.Manifests{},
+		false,
 		mockUserContextReader,
 	)
 }
@@ -91,5 +251,6 @@ func setupPrivateUsecase(
 		},
 		quotas:            quotas,
 		userContextReader: mockUserContextReader,
+		onboardings:       new(singleflight.Group),
 	}
 }
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+)
+
+func (s *onboardingUsecase) applyLimitRange(
+	ctx context.Context,
+	namespace string,
+	quota *domain.Quota,
+) error {
+	// 🔹 No LimitRange configured for this profile, remove the one left by a previous profile
+	if quota.LimitRange == (domain.LimitRange{}) {
+		result, err := s.namespaceService.DeleteLimitRange(ctx, namespace)
+		if err != nil {
+			slog.ErrorContext(ctx, "❌ Failed to delete limit range",
+				slog.String("namespace", namespace),
+				slog.Any("error", err),
+			)
+			return fmt.Errorf("failed to delete limit range of namespace (%s): %w", namespace, err)
+		}
+		if result == interfaces.LimitRangeDeleted {
+			slog.InfoContext(ctx, "✅ Deleted limit range no longer configured",
+				slog.String("namespace", namespace),
+			)
+		}
+		return nil
+	}
+
+	result, err := s.namespaceService.ApplyLimitRange(ctx, namespace, &quota.LimitRange)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to apply limit range",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to apply limit range to namespace (%s): %w", namespace, err)
+	}
+
+	switch result {
+	case interfaces.LimitRangeCreated:
+		slog.InfoContext(ctx, "✅ Created new limit range",
+			slog.String("namespace", namespace),
+		)
+	case interfaces.LimitRangeUpdated:
+		slog.InfoContext(ctx, "✅ Updated limit range",
+			slog.String("namespace", namespace),
+		)
+	case interfaces.LimitRangeUnchanged:
+		slog.WarnContext(ctx, "⚠️ Limit range is already up-to-date",
+			slog.String("namespace", namespace),
+		)
+	case interfaces.LimitRangeIgnored:
+		slog.WarnContext(ctx, "⚠️ Limit range ignored due to annotation",
+			slog.String("namespace", namespace),
+		)
+	}
+
+	return nil
+}
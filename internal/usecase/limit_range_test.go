@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApplyQuotas_WithLimitRange(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{
+		Enabled: true,
+		Default: domain.Quota{
+			CPULimit:   "10",
+			LimitRange: domain.LimitRange{DefaultCPU: "1", DefaultMemory: "2Gi"},
+		},
+	}
+	usecase := setupPrivateUsecase(mockService, quotas)
+
+	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
+		Return(interfaces.QuotaCreated, nil)
+	mockService.On("ApplyLimitRange", mock.Anything, userNamespace, &quotas.Default.LimitRange).
+		Return(interfaces.LimitRangeCreated, nil)
+
+	_, _, err := usecase.applyQuotas(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(
+		t,
+		"ApplyLimitRange",
+		mock.Anything,
+		userNamespace,
+		&quotas.Default.LimitRange,
+	)
+}
+
+// ✅ Test a profile without limit range removes the one left by a previous profile
+func TestApplyLimitRange_NotConfigured(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeDeleted, nil)
+
+	err := usecase.applyLimitRange(
+		context.Background(),
+		userNamespace,
+		&domain.Quota{CPULimit: "1"},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "ApplyLimitRange")
+	mockService.AssertExpectations(t)
+}
+
+// ❌ Test a failure deleting the limit range is reported
+func TestApplyLimitRange_DeleteFailure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeApplicationResult(""), errors.New("forbidden"))
+
+	err := usecase.applyLimitRange(context.Background(), userNamespace, &domain.Quota{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete limit range")
+}
+
+func TestApplyLimitRange_Failure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	quota := &domain.Quota{LimitRange: domain.LimitRange{MaxCPU: "4"}}
+
+	mockService.On("ApplyLimitRange", mock.Anything, userNamespace, &quota.LimitRange).
+		Return(interfaces.LimitRangeApplicationResult(""), errors.New("forbidden"))
+
+	err := usecase.applyLimitRange(context.Background(), userNamespace, quota)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply limit range")
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+)
+
+func (s *onboardingUsecase) applyManifests(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	if !s.manifests.Enabled {
+		return nil
+	}
+
+	data := s.getManifestData(ctx, namespace, req)
+
+	var applied []domain.ManifestObject
+	for _, manifest := range s.manifests.Templates {
+		var rendered bytes.Buffer
+		if err := manifest.Template.Execute(&rendered, data); err != nil {
+			slog.ErrorContext(ctx, "❌ Failed to render manifest",
+				slog.String("namespace", namespace),
+				slog.String("manifest", manifest.Name),
+				slog.Any("error", err),
+			)
+			return fmt.Errorf("failed to render manifest %s: %w", manifest.Name, err)
+		}
+
+		results, err := s.manifestService.ApplyManifest(ctx, namespace, rendered.String())
+		if err != nil {
+			slog.ErrorContext(ctx, "❌ Failed to apply manifest",
+				slog.String("namespace", namespace),
+				slog.String("manifest", manifest.Name),
+				slog.Any("error", err),
+			)
+			return fmt.Errorf(
+				"failed to apply manifest %s to namespace (%s): %w",
+				manifest.Name,
+				namespace,
+				err,
+			)
+		}
+
+		for _, result := range results {
+			logManifestResult(ctx, namespace, manifest.Name, result)
+			applied = append(applied, result.Object)
+		}
+	}
+
+	pruned, err := s.manifestService.PruneManifests(ctx, namespace, applied)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to prune manifests",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to prune manifests in namespace (%s): %w", namespace, err)
+	}
+
+	if pruned > 0 {
+		slog.InfoContext(ctx, "✅ Pruned objects of removed manifests",
+			slog.String("namespace", namespace),
+			slog.Int("count", pruned),
+		)
+	}
+
+	return nil
+}
+
+func (s *onboardingUsecase) getManifestData(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) domain.ManifestData {
+	data := domain.ManifestData{
+		Namespace: namespace,
+		Region:    s.manifests.Region,
+	}
+
+	if req.Group != nil {
+		data.Group = *req.Group
+	}
+
+	if user, ok := s.userContextReader.GetUser(ctx); ok {
+		data.User = *user
+	} else {
+		data.User = domain.User{
+			Username: req.UserName,
+			Groups:   req.UserGroups,
+			Roles:    req.UserRoles,
+		}
+	}
+
+	return data
+}
+
+func logManifestResult(
+	ctx context.Context,
+	namespace string,
+	manifest string,
+	applied interfaces.AppliedManifestObject,
+) {
+	attrs := []any{
+		slog.String("namespace", namespace),
+		slog.String("manifest", manifest),
+		slog.String("kind", applied.Object.Kind),
+		slog.String("name", applied.Object.Name),
+	}
+
+	switch applied.Result {
+	case interfaces.ManifestCreated:
+		slog.InfoContext(ctx, "✅ Created manifest object", attrs...)
+	case interfaces.ManifestUpdated:
+		slog.InfoContext(ctx, "✅ Updated manifest object", attrs...)
+	case interfaces.ManifestUnchanged:
+		slog.InfoContext(ctx, "Manifest object is already up-to-date", attrs...)
+	case interfaces.ManifestIgnored:
+		slog.WarnContext(ctx, "⚠️ Manifest object ignored due to annotation", attrs...)
+	}
+}
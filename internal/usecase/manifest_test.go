@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"text/template"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testManifestTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: onyxia-context
+data:
+  user: {{ .User.Username }}
+  namespace: {{ .Namespace }}
+  group: "{{ .Group }}"
+  region: {{ .Region }}
+`
+
+func testManifests(t *testing.T) domain.Manifests {
+	tmpl, err := template.New("context.yaml").Parse(testManifestTemplate)
+	assert.NoError(t, err)
+
+	return domain.Manifests{
+		Enabled:   true,
+		Region:    "eu-west",
+		Templates: []domain.Manifest{{Name: "context.yaml", Template: tmpl}},
+	}
+}
+
+var contextConfigMap = domain.ManifestObject{
+	APIVersion: "v1",
+	Kind:       "ConfigMap",
+	Name:       "onyxia-context",
+}
+
+func TestApplyManifests_User(t *testing.T) {
+	mockManifestService := new(MockManifestService)
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.manifestService = mockManifestService
+	usecase.manifests = testManifests(t)
+
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: onyxia-context
+data:
+  user: test-user
+  namespace: user-test-user
+  group: ""
+  region: eu-west
+`
+
+	mockManifestService.On("ApplyManifest", mock.Anything, userNamespace, expected).
+		Return([]interfaces.AppliedManifestObject{
+			{Object: contextConfigMap, Result: interfaces.ManifestCreated},
+		}, nil)
+	mockManifestService.On(
+		"PruneManifests",
+		mock.Anything,
+		userNamespace,
+		[]domain.ManifestObject{contextConfigMap},
+	).Return(0, nil)
+
+	err := usecase.applyManifests(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockManifestService.AssertExpectations(t)
+}
+
+func TestApplyManifests_Group(t *testing.T) {
+	mockManifestService := new(MockManifestService)
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.manifestService = mockManifestService
+	usecase.manifests = testManifests(t)
+
+	mockManifestService.On(
+		"ApplyManifest",
+		mock.Anything,
+		groupNamespace,
+		mock.MatchedBy(func(manifest string) bool {
+			return assert.Contains(t, manifest, `group: "test-group"`)
+		}),
+	).Return([]interfaces.AppliedManifestObject{
+		{Object: contextConfigMap, Result: interfaces.ManifestUnchanged},
+	}, nil)
+	mockManifestService.On("PruneManifests", mock.Anything, groupNamespace, mock.Anything).
+		Return(1, nil)
+
+	groupName := testGroupName
+	err := usecase.applyManifests(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockManifestService.AssertExpectations(t)
+}
+
+func TestApplyManifests_Disabled(t *testing.T) {
+	mockManifestService := new(MockManifestService)
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.manifestService = mockManifestService
+
+	err := usecase.applyManifests(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockManifestService.AssertNotCalled(t, "ApplyManifest")
+	mockManifestService.AssertNotCalled(t, "PruneManifests")
+}
+
+func TestApplyManifests_Failure(t *testing.T) {
+	mockManifestService := new(MockManifestService)
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.manifestService = mockManifestService
+	usecase.manifests = testManifests(t)
+
+	mockManifestService.On("ApplyManifest", mock.Anything, userNamespace, mock.Anything).
+		Return([]interfaces.AppliedManifestObject(nil), errors.New("forbidden"))
+
+	err := usecase.applyManifests(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "context.yaml")
+	mockManifestService.AssertNotCalled(t, "PruneManifests")
+}
@@ -4,29 +4,40 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
 )
 
-func (s *onboardingUsecase) createNamespace(ctx context.Context, name string) error {
+func (s *onboardingUsecase) createNamespace(
+	ctx context.Context,
+	name string,
+	annotations map[string]string,
+	labels map[string]string,
+) (interfaces.NamespaceCreationResult, error) {
 	result, err := s.namespaceService.CreateNamespace(
 		ctx,
 		name,
-		s.getNamespaceAnnotations(ctx),
-		s.namespace.NamespaceLabels,
+		annotations,
+		labels,
 	)
-
-	slog.Info("result create Namespace", slog.String("result", string(result)))
-
 	if err != nil {
 		slog.ErrorContext(ctx, "❌ Failed to create namespace",
 			slog.String("namespace", name),
 			slog.Any("error", err),
 		)
-		return err
+		return "", err
 	}
 
+	slog.DebugContext(ctx, "🔹 Namespace creation result",
+		slog.String("namespace", name),
+		slog.String("result", string(result)),
+	)
+
 	switch result {
 	case interfaces.NamespaceCreated:
 		slog.InfoContext(ctx, "✅ Successfully created namespace",
@@ -36,9 +47,36 @@ func (s *onboardingUsecase) createNamespace(ctx context.Context, name string) er
 		slog.WarnContext(ctx, "⚠️ Namespace already exists",
 			slog.String("namespace", name),
 		)
+	case interfaces.NamespaceNotOwned:
+		return "", fmt.Errorf(
+			"%w: namespace %s already exists and was not created by onboarding",
+			domain.ErrNamespaceCollision,
+			name,
+		)
 	}
 
-	return nil
+	return result, nil
+}
+
+// namespaceAnnotations returns the configured annotations along with the identity annotation and,
+// for user namespaces, the roles annotation.
+func (s *onboardingUsecase) namespaceAnnotations(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) map[string]string {
+	annotations := s.getNamespaceAnnotations(ctx)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[domain.IdentityAnnotation] = getIdentity(req)
+
+	if req.Group == nil {
+		roles := slices.Clone(req.UserRoles)
+		slices.Sort(roles)
+		annotations[domain.RolesAnnotation] = strings.Join(slices.Compact(roles), ",")
+	}
+
+	return annotations
 }
 
 func (s *onboardingUsecase) getNamespaceAnnotations(
@@ -48,13 +86,14 @@ func (s *onboardingUsecase) getNamespaceAnnotations(
 		return nil
 	}
 
-	annotations := s.namespace.Annotation.Static
+	// 🔹 The static annotations are shared by concurrent onboardings
+	annotations := maps.Clone(s.namespace.Annotation.Static)
 	if annotations == nil {
 		annotations = make(map[string]string)
 	}
 
 	if s.namespace.Annotation.Dynamic.LastLoginTimestamp {
-		annotations["onyxia_last_login_timestamp"] = fmt.Sprint(time.Now().UnixMilli())
+		annotations[domain.LastLoginAnnotation] = fmt.Sprint(time.Now().UnixMilli())
 	}
 
 	if attributes, ok := s.userContextReader.GetAttributes(ctx); ok {
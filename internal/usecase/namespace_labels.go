@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"maps"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+)
+
+const maxLabelValueLength = 63
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitizeLabelValue derives a valid label value: runs of invalid characters are replaced by a
+// single '-', and it is truncated to 63 characters starting and ending with an alphanumeric one.
+func sanitizeLabelValue(value string) string {
+	value = invalidLabelValueChars.ReplaceAllString(value, "-")
+	value = strings.TrimFunc(value, isNotAlphanumeric)
+	value = value[:min(len(value), maxLabelValueLength)]
+	return strings.TrimRightFunc(value, isNotAlphanumeric)
+}
+
+func isNotAlphanumeric(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// namespaceLabels returns the static labels along with the dynamic ones rendered for req. A
+// dynamic label that cannot be rendered, e.g. because of a missing claim, or is empty is left out.
+func (s *onboardingUsecase) namespaceLabels(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) map[string]string {
+	if len(s.namespace.DynamicLabels) == 0 {
+		return s.namespace.NamespaceLabels
+	}
+
+	labels := maps.Clone(s.namespace.NamespaceLabels)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	data := s.namespaceNameData(ctx, req)
+	for _, label := range s.namespace.DynamicLabels {
+		var rendered bytes.Buffer
+		if err := label.Template.Execute(&rendered, data); err != nil {
+			slog.WarnContext(ctx, "⚠️ Failed to render dynamic label, skipping it",
+				slog.String("label", label.Name),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		if value := sanitizeLabelValue(rendered.String()); value != "" {
+			labels[label.Name] = value
+		}
+	}
+
+	return labels
+}
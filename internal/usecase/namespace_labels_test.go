@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	usercontext "github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	tests := map[string]string{
+		"DG75":                         "DG75",
+		"Cost Center / 42":             "Cost-Center-42",
+		"_data.science_":               "data.science",
+		"été":                          "t",
+		"---":                          "",
+		strings.Repeat("a", 62) + "-b": strings.Repeat("a", 62),
+	}
+
+	for value, expected := range tests {
+		assert.Equal(t, expected, sanitizeLabelValue(value), value)
+	}
+}
+
+func TestNamespaceLabels_Dynamic(t *testing.T) {
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Attributes: map[string]any{"department": "DG 75", "cost_center": "42"},
+	})
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.userContextReader = mockUserCtx
+	usecase.namespace.NamespaceLabels = map[string]string{"created-by": "onyxia"}
+
+	for name, value := range map[string]string{
+		"onyxia.sh/department":  `{{ .Claims.department }}`,
+		"onyxia.sh/cost-center": `{{ .Claims.cost_center }}`,
+		"onyxia.sh/team":        `{{ .Group | trimPrefix "/org/" }}`,
+		"onyxia.sh/missing":     `{{ .Claims.missing }}`,
+	} {
+		tmpl, err := ParseNamespaceTemplate(name, value)
+		assert.NoError(t, err)
+		usecase.namespace.DynamicLabels = append(
+			usecase.namespace.DynamicLabels,
+			domain.DynamicLabel{Name: name, Template: tmpl},
+		)
+	}
+
+	group := "/org/data"
+	labels := usecase.namespaceLabels(context.Background(), domain.OnboardingRequest{
+		Group:    &group,
+		UserName: testUserName,
+	})
+
+	// ✅ The missing claim is left out, the static labels are kept
+	assert.Equal(t, map[string]string{
+		"created-by":            "onyxia",
+		"onyxia.sh/department":  "DG-75",
+		"onyxia.sh/cost-center": "42",
+		"onyxia.sh/team":        "data",
+	}, labels)
+	assert.Len(t, usecase.namespace.NamespaceLabels, 1, "Expected static labels to be untouched")
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	maxNamespaceNameLength = 63
+	namespaceHashLength    = 8
+)
+
+var invalidNamespaceChars = regexp.MustCompile(`[^a-z0-9-]+`)
+var repeatedDashes = regexp.MustCompile(`-{2,}`)
+
+// normalizeNamespaceName derives a valid DNS-1123 label from a prefix and a user or group name.
+// Valid labels are kept as they are, so that existing namespaces keep their name. Others are
+// lowercased and runs of invalid characters are replaced by a single '-'. Names longer than 63
+// characters are truncated and suffixed with a hash of the original name to stay unique.
+func normalizeNamespaceName(prefix, name string) string {
+	original := prefix + name
+	if len(validation.IsDNS1123Label(original)) == 0 {
+		return original
+	}
+
+	normalized := invalidNamespaceChars.ReplaceAllString(strings.ToLower(original), "-")
+	normalized = strings.Trim(repeatedDashes.ReplaceAllString(normalized, "-"), "-")
+
+	if normalized != "" && len(normalized) <= maxNamespaceNameLength {
+		return normalized
+	}
+
+	hash := sha256.Sum256([]byte(original))
+	suffix := hex.EncodeToString(hash[:])[:namespaceHashLength]
+
+	maxLength := maxNamespaceNameLength - namespaceHashLength - 1
+	truncated := strings.TrimRight(normalized[:min(len(normalized), maxLength)], "-")
+	if truncated == "" {
+		return suffix
+	}
+
+	return truncated + "-" + suffix
+}
+
+// namespaceTemplateFuncs are the functions available in namespace name templates. Their last
+// argument is the piped value, e.g. `{{ .Group | trimPrefix "/org/" }}`.
+var namespaceTemplateFuncs = template.FuncMap{
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+}
+
+// ParseNamespaceTemplate parses a namespace name template and checks that it can be rendered, so
+// that typos in field or function names are reported at startup.
+func ParseNamespaceTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).
+		Funcs(namespaceTemplateFuncs).
+		Option("missingkey=error").
+		Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🔹 Claims depend on the user, only fields and functions can be checked here
+	sample, err := tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sample.Option("missingkey=zero").Execute(&bytes.Buffer{}, domain.NamespaceNameData{
+		Claims: map[string]any{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// getNamespace returns the name of the namespace of req, which must not be reserved.
+func (s *onboardingUsecase) getNamespace(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (string, error) {
+	name, err := s.renderNamespaceName(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if isReserved(s.namespace.ReservedNames, name) {
+		return "", fmt.Errorf("%w: namespace %s is reserved", domain.ErrNamespaceNotOwned, name)
+	}
+
+	return name, nil
+}
+
+// isReserved tells whether name matches one of the reserved names or glob patterns.
+func isReserved(reservedNames []string, name string) bool {
+	for _, pattern := range reservedNames {
+		if reserved, _ := path.Match(pattern, name); reserved {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *onboardingUsecase) renderNamespaceName(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (string, error) {
+	prefix, tmpl := s.namespace.NamespacePrefix, s.namespace.NameTemplate
+	if req.Group != nil {
+		prefix, tmpl = s.namespace.GroupNamespacePrefix, s.namespace.GroupNameTemplate
+	}
+
+	if tmpl == nil {
+		if req.Group != nil {
+			return normalizeNamespaceName(prefix, *req.Group), nil
+		}
+		return normalizeNamespaceName(prefix, req.UserName), nil
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, s.namespaceNameData(ctx, req)); err != nil {
+		return "", fmt.Errorf("failed to render namespace name: %w", err)
+	}
+
+	name := strings.TrimSpace(rendered.String())
+	if name == "" {
+		return "", fmt.Errorf("namespace name template %s rendered an empty name", tmpl.Name())
+	}
+
+	return normalizeNamespaceName("", name), nil
+}
+
+// namespaceNameData returns the data namespace name and dynamic label templates are rendered with.
+func (s *onboardingUsecase) namespaceNameData(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) domain.NamespaceNameData {
+	data := domain.NamespaceNameData{
+		Username: req.UserName,
+		Roles:    req.UserRoles,
+		Groups:   req.UserGroups,
+	}
+	if req.Group != nil {
+		data.Group = *req.Group
+	}
+	if claims, ok := s.userContextReader.GetAttributes(ctx); ok {
+		data.Claims = claims
+	}
+	return data
+}
+
+// getIdentity returns the value of the identity annotation of the requested namespace.
+func getIdentity(req domain.OnboardingRequest) string {
+	if req.Group != nil {
+		return "group:" + *req.Group
+	}
+	return "user:" + req.UserName
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	usercontext "github.com/onyxia-datalab/onyxia-onboarding/internal/infrastructure/context"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestNormalizeNamespaceName(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		identity string
+		expected string
+	}{
+		{"valid name is kept", "user-", "jdupont", "user-jdupont"},
+		{"repeated dashes of a valid name are kept", "user-", "a--b", "user-a--b"},
+		{"uppercase is lowered", "user-", "JDupont", "user-jdupont"},
+		{"email", "user-", "Jean.Dupont@insee.fr", "user-jean-dupont-insee-fr"},
+		{"group path", "projet-", "/dep/Team_A", "projet-dep-team-a"},
+		{"trailing invalid characters", "", "jdupont_", "jdupont"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeNamespaceName(tt.prefix, tt.identity))
+		})
+	}
+}
+
+func TestNormalizeNamespaceName_Truncated(t *testing.T) {
+	long := strings.Repeat("a", 80)
+
+	name := normalizeNamespaceName("projet-", long)
+	other := normalizeNamespaceName("projet-", long+"b")
+
+	assert.Len(t, name, 63)
+	assert.Empty(t, validation.IsDNS1123Label(name))
+	assert.True(t, strings.HasPrefix(name, "projet-aaa"))
+	assert.NotEqual(t, name, other, "Expected the hash suffix to keep truncated names unique")
+	assert.Equal(t, name, normalizeNamespaceName("projet-", long), "Expected a stable name")
+}
+
+func TestNormalizeNamespaceName_OnlyInvalidCharacters(t *testing.T) {
+	name := normalizeNamespaceName("", "@@@")
+
+	assert.Len(t, name, namespaceHashLength)
+	assert.Empty(t, validation.IsDNS1123Label(name))
+}
+
+func TestGetIdentity(t *testing.T) {
+	groupName := "Team_A"
+
+	assert.Equal(t, "group:Team_A", getIdentity(domain.OnboardingRequest{Group: &groupName}))
+	assert.Equal(
+		t,
+		"user:Jean.Dupont",
+		getIdentity(domain.OnboardingRequest{UserName: "Jean.Dupont"}),
+	)
+}
+
+func TestGetNamespace_Template(t *testing.T) {
+	mockUserCtx, _ := usercontext.NewFakeUserContext(&domain.User{
+		Attributes: map[string]any{"department": "DG75"},
+	})
+
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.userContextReader = mockUserCtx
+
+	var err error
+	usecase.namespace.NameTemplate, err = ParseNamespaceTemplate(
+		"namespaceTemplate",
+		`{{ .Claims.department }}-{{ .Username }}`,
+	)
+	assert.NoError(t, err)
+	usecase.namespace.GroupNameTemplate, err = ParseNamespaceTemplate(
+		"groupNamespaceTemplate",
+		`team-{{ .Group | trimPrefix "/org/" }}`,
+	)
+	assert.NoError(t, err)
+
+	namespace, err := usecase.getNamespace(
+		context.Background(),
+		domain.OnboardingRequest{UserName: "Jean.Dupont"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "dg75-jean-dupont", namespace)
+
+	group := "/org/data"
+	namespace, err = usecase.getNamespace(
+		context.Background(),
+		domain.OnboardingRequest{Group: &group, UserName: "Jean.Dupont"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "team-data", namespace)
+}
+
+func TestGetNamespace_TemplateMissingClaim(t *testing.T) {
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+
+	var err error
+	usecase.namespace.NameTemplate, err = ParseNamespaceTemplate(
+		"namespaceTemplate",
+		`{{ .Claims.department }}-{{ .Username }}`,
+	)
+	assert.NoError(t, err)
+
+	_, err = usecase.getNamespace(
+		context.Background(),
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render namespace name")
+}
+
+func TestParseNamespaceTemplate_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"syntax":           `user-{{ .Username `,
+		"unknown function": `user-{{ .Username | slugify }}`,
+		"unknown field":    `user-{{ .Usernam }}`,
+	}
+
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseNamespaceTemplate("namespaceTemplate", text)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGetNamespace_Reserved(t *testing.T) {
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.namespace.NamespacePrefix = ""
+	usecase.namespace.GroupNamespacePrefix = ""
+	usecase.namespace.ReservedNames = []string{"kube", "kube-*", "system"}
+	group := "system"
+
+	for _, req := range []domain.OnboardingRequest{
+		{UserName: "kube"},
+		{UserName: "Kube-System"},
+		{Group: &group, UserName: testUserName},
+	} {
+		_, err := usecase.getNamespace(context.Background(), req)
+		assert.ErrorIs(t, err, domain.ErrNamespaceNotOwned)
+	}
+
+	namespace, err := usecase.getNamespace(
+		context.Background(),
+		domain.OnboardingRequest{UserName: "kubeflow"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "kubeflow", namespace)
+}
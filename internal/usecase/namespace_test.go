@@ -20,9 +20,10 @@ func TestCreateNamespace_Success(t *testing.T) {
 	mockService.On("CreateNamespace", mock.Anything, userNamespace).
 		Return(interfaces.NamespaceCreated, nil)
 
-	err := usecase.createNamespace(context.Background(), userNamespace)
+	result, err := usecase.createNamespace(context.Background(), userNamespace, nil, nil)
 
 	assert.NoError(t, err)
+	assert.Equal(t, interfaces.NamespaceCreated, result)
 	mockService.AssertCalled(t, "CreateNamespace", mock.Anything, userNamespace)
 }
 
@@ -33,7 +34,7 @@ func TestCreateNamespace_AlreadyExists(t *testing.T) {
 	mockService.On("CreateNamespace", mock.Anything, userNamespace).
 		Return(interfaces.NamespaceAlreadyExists, nil)
 
-	err := usecase.createNamespace(context.Background(), userNamespace)
+	_, err := usecase.createNamespace(context.Background(), userNamespace, nil, nil)
 
 	assert.NoError(t, err)
 	mockService.AssertCalled(t, "CreateNamespace", mock.Anything, userNamespace)
@@ -45,12 +46,47 @@ func TestCreateNamespace_Failure(t *testing.T) {
 
 	mockService.On("CreateNamespace", mock.Anything, userNamespace).
 		Return(interfaces.NamespaceCreationResult(""), errors.New("failed to create namespace"))
-	err := usecase.createNamespace(context.Background(), userNamespace)
+	_, err := usecase.createNamespace(context.Background(), userNamespace, nil, nil)
 
 	assert.Error(t, err)
 	mockService.AssertCalled(t, "CreateNamespace", mock.Anything, userNamespace)
 }
 
+func TestCreateNamespace_NotOwned(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	mockService.On("CreateNamespace", mock.Anything, userNamespace).
+		Return(interfaces.NamespaceNotOwned, nil)
+
+	_, err := usecase.createNamespace(context.Background(), userNamespace, nil, nil)
+
+	assert.ErrorIs(t, err, domain.ErrNamespaceCollision)
+}
+
+func TestNamespaceAnnotations_Identity(t *testing.T) {
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.namespace.Annotation.Enabled = true
+	usecase.namespace.Annotation.Static = map[string]string{"static-key": "static-value"}
+
+	annotations := usecase.namespaceAnnotations(context.Background(), domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"developer", "admin", "developer"},
+	})
+
+	assert.Equal(t, map[string]string{
+		"static-key":              "static-value",
+		domain.IdentityAnnotation: "user:" + testUserName,
+		domain.RolesAnnotation:    "admin,developer",
+	}, annotations)
+	assert.NotContains(
+		t,
+		usecase.namespace.Annotation.Static,
+		domain.IdentityAnnotation,
+		"Expected the configured annotations to be left untouched",
+	)
+}
+
 func TestGetNamespaceAnnotations_Disabled(t *testing.T) {
 	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
 	usecase.namespace.Annotation.Enabled = false
@@ -73,6 +109,22 @@ func TestGetNamespaceAnnotations_StaticOnly(t *testing.T) {
 	assert.Equal(t, "static-value", annotations["static-key"])
 }
 
+func TestGetNamespaceAnnotations_StaticUnchanged(t *testing.T) {
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	usecase.namespace.Annotation.Enabled = true
+	usecase.namespace.Annotation.Dynamic.LastLoginTimestamp = true
+	usecase.namespace.Annotation.Static = map[string]string{
+		"static-key": "static-value",
+	}
+
+	annotations := usecase.getNamespaceAnnotations(context.Background())
+
+	assert.Contains(t, annotations, domain.LastLoginAnnotation)
+	assert.Equal(t, map[string]string{"static-key": "static-value"},
+		usecase.namespace.Annotation.Static,
+		"Expected the static annotations shared by onboardings to be left as they are")
+}
+
 func TestGetNamespaceAnnotations_LastLoginTimestamp(t *testing.T) {
 	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
 	usecase.namespace.Annotation.Enabled = true
@@ -131,3 +183,18 @@ func TestGetNamespaceAnnotations_AllAnnotations(t *testing.T) {
 	assert.Contains(t, annotations, "onyxia_last_login_timestamp")
 	assert.Equal(t, "value1", annotations["user-attr1"])
 }
+
+func TestNamespaceAnnotations_GroupHasNoRoles(t *testing.T) {
+	usecase := setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{})
+	group := testGroupName
+
+	annotations := usecase.namespaceAnnotations(context.Background(), domain.OnboardingRequest{
+		Group:     &group,
+		UserName:  testUserName,
+		UserRoles: []string{"admin"},
+	})
+
+	assert.Equal(t, map[string]string{
+		domain.IdentityAnnotation: "group:" + testGroupName,
+	}, annotations)
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+)
+
+func (s *onboardingUsecase) applyNetworkPolicies(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	if !s.networkPolicies.Enabled {
+		return nil
+	}
+
+	policies := s.networkPolicies.User
+	if req.Group != nil {
+		policies = s.networkPolicies.Group
+	}
+
+	keep := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		keep = append(keep, policy.Name)
+
+		result, err := s.namespaceService.ApplyNetworkPolicy(ctx, namespace, &policy)
+		if err != nil {
+			slog.ErrorContext(ctx, "❌ Failed to apply network policy",
+				slog.String("namespace", namespace),
+				slog.String("networkPolicy", policy.Name),
+				slog.Any("error", err),
+			)
+			return fmt.Errorf(
+				"failed to apply network policy %s to namespace (%s): %w",
+				policy.Name,
+				namespace,
+				err,
+			)
+		}
+
+		switch result {
+		case interfaces.NetworkPolicyCreated:
+			slog.InfoContext(ctx, "✅ Created network policy",
+				slog.String("namespace", namespace),
+				slog.String("networkPolicy", policy.Name),
+			)
+		case interfaces.NetworkPolicyUpdated:
+			slog.InfoContext(ctx, "✅ Updated network policy",
+				slog.String("namespace", namespace),
+				slog.String("networkPolicy", policy.Name),
+			)
+		case interfaces.NetworkPolicyUnchanged:
+			slog.InfoContext(ctx, "Network policy is already up-to-date",
+				slog.String("namespace", namespace),
+				slog.String("networkPolicy", policy.Name),
+			)
+		case interfaces.NetworkPolicyIgnored:
+			slog.WarnContext(ctx, "⚠️ Network policy ignored due to annotation",
+				slog.String("namespace", namespace),
+				slog.String("networkPolicy", policy.Name),
+			)
+		}
+	}
+
+	// 🔹 Policies removed from the configuration would keep restricting the namespace
+	pruned, err := s.namespaceService.PruneNetworkPolicies(ctx, namespace, keep)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to prune network policies",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to prune network policies of namespace (%s): %w", namespace, err)
+	}
+
+	if pruned > 0 {
+		slog.InfoContext(ctx, "✅ Pruned network policies",
+			slog.String("namespace", namespace),
+			slog.Int("count", pruned),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testNetworkPolicies = domain.NetworkPolicies{
+	Enabled: true,
+	User: []domain.NetworkPolicy{
+		{Name: "deny-all-ingress", Spec: "podSelector: {}"},
+		{Name: "allow-same-namespace", Spec: "podSelector: {}"},
+	},
+	Group: []domain.NetworkPolicy{
+		{Name: "group-policy", Spec: "podSelector: {}"},
+	},
+}
+
+func TestApplyNetworkPolicies_User(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies
+
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, mock.Anything).
+		Return(interfaces.NetworkPolicyCreated, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, userNamespace,
+		[]string{"deny-all-ingress", "allow-same-namespace"}).
+		Return(0, nil)
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNumberOfCalls(t, "ApplyNetworkPolicy", 2)
+	mockService.AssertCalled(
+		t,
+		"ApplyNetworkPolicy",
+		mock.Anything,
+		userNamespace,
+		&testNetworkPolicies.User[0],
+	)
+}
+
+func TestApplyNetworkPolicies_Group(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies
+
+	mockService.On("ApplyNetworkPolicy", mock.Anything, groupNamespace, mock.Anything).
+		Return(interfaces.NetworkPolicyUnchanged, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, groupNamespace, []string{"group-policy"}).
+		Return(1, nil)
+
+	groupName := testGroupName
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNumberOfCalls(t, "ApplyNetworkPolicy", 1)
+	mockService.AssertCalled(
+		t,
+		"ApplyNetworkPolicy",
+		mock.Anything,
+		groupNamespace,
+		&testNetworkPolicies.Group[0],
+	)
+}
+
+func TestApplyNetworkPolicies_Disabled(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "ApplyNetworkPolicy")
+	mockService.AssertNotCalled(t, "PruneNetworkPolicies")
+}
+
+func TestApplyNetworkPolicies_Failure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies
+
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, mock.Anything).
+		Return(interfaces.NetworkPolicyApplicationResult(""), errors.New("forbidden"))
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "deny-all-ingress")
+	mockService.AssertNumberOfCalls(t, "ApplyNetworkPolicy", 1)
+}
+
+func TestApplyNetworkPolicies_PruneFailure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.networkPolicies = testNetworkPolicies
+
+	mockService.On("ApplyNetworkPolicy", mock.Anything, userNamespace, mock.Anything).
+		Return(interfaces.NetworkPolicyUnchanged, nil)
+	mockService.On("PruneNetworkPolicies", mock.Anything, userNamespace, mock.Anything).
+		Return(0, errors.New("forbidden"))
+
+	err := usecase.applyNetworkPolicies(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to prune network policies")
+}
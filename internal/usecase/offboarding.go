@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+)
+
+func (s *onboardingUsecase) Offboard(ctx context.Context, req domain.OffboardingRequest) error {
+	onboardingReq := domain.OnboardingRequest{Group: req.Group, UserName: req.UserName}
+	namespace, err := s.getNamespace(ctx, onboardingReq)
+	if err != nil {
+		return err
+	}
+
+	if !s.offboarding.Enabled {
+		slog.WarnContext(ctx, "⚠️ Offboarding is disabled, refusing to offboard namespace",
+			slog.String("namespace", namespace),
+		)
+		return fmt.Errorf("offboarding is disabled")
+	}
+
+	// 🔹 Any member can offboard a group namespace, so it is only archived and can be restored
+	mode := s.offboarding.Mode
+	if req.Group != nil && mode == domain.OffboardingModeDelete {
+		slog.InfoContext(ctx, "🔹 Archiving group namespace instead of deleting it",
+			slog.String("namespace", namespace),
+		)
+		mode = domain.OffboardingModeArchive
+	}
+
+	result, err := s.namespaceService.OffboardNamespace(
+		ctx,
+		namespace,
+		getIdentity(onboardingReq),
+		mode,
+		s.offboarding.GracePeriod,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to offboard namespace",
+			slog.String("namespace", namespace),
+			slog.String("mode", string(mode)),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to offboard namespace (%s): %w", namespace, err)
+	}
+
+	switch result {
+	case interfaces.NamespaceDeleted:
+		slog.InfoContext(ctx, "✅ Deleted namespace",
+			slog.String("namespace", namespace),
+		)
+	case interfaces.NamespaceArchived:
+		slog.InfoContext(ctx, "✅ Archived namespace",
+			slog.String("namespace", namespace),
+			slog.Duration("gracePeriod", s.offboarding.GracePeriod),
+		)
+	case interfaces.NamespaceAlreadyArchived:
+		slog.WarnContext(ctx, "⚠️ Namespace is already archived",
+			slog.String("namespace", namespace),
+		)
+	case interfaces.NamespaceNotFound:
+		slog.WarnContext(ctx, "⚠️ Namespace does not exist, nothing to offboard",
+			slog.String("namespace", namespace),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupOffboardingUsecase(
+	mockService *MockNamespaceService,
+	offboarding domain.Offboarding,
+) *onboardingUsecase {
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.offboarding = offboarding
+	return usecase
+}
+
+// ✅ Test `Offboard` Success (User namespace archived)
+func Test_Offboard_Archive(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupOffboardingUsecase(mockService, domain.Offboarding{
+		Enabled:     true,
+		Mode:        domain.OffboardingModeArchive,
+		GracePeriod: 24 * time.Hour,
+	})
+
+	mockService.On(
+		"OffboardNamespace",
+		mock.Anything,
+		userNamespace,
+		"user:"+testUserName,
+		domain.OffboardingModeArchive,
+		24*time.Hour,
+	).
+		Return(interfaces.NamespaceArchived, nil)
+
+	err := usecase.Offboard(context.Background(), domain.OffboardingRequest{UserName: testUserName})
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(
+		t,
+		"OffboardNamespace",
+		mock.Anything,
+		userNamespace,
+		"user:"+testUserName,
+		domain.OffboardingModeArchive,
+		24*time.Hour,
+	)
+}
+
+// ✅ Test `Offboard` only archives a group namespace, even in delete mode
+func Test_Offboard_GroupArchived(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupOffboardingUsecase(mockService, domain.Offboarding{
+		Enabled:     true,
+		Mode:        domain.OffboardingModeDelete,
+		GracePeriod: 24 * time.Hour,
+	})
+
+	mockService.On(
+		"OffboardNamespace",
+		mock.Anything,
+		groupNamespace,
+		"group:"+testGroupName,
+		domain.OffboardingModeArchive,
+		24*time.Hour,
+	).
+		Return(interfaces.NamespaceArchived, nil)
+
+	groupName := testGroupName
+	err := usecase.Offboard(
+		context.Background(),
+		domain.OffboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(
+		t,
+		"OffboardNamespace",
+		mock.Anything,
+		groupNamespace,
+		mock.Anything,
+		domain.OffboardingModeDelete,
+		mock.Anything,
+	)
+}
+
+// ❌ Test `Offboard` (Offboarding Disabled)
+func Test_Offboard_Disabled(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupOffboardingUsecase(mockService, domain.Offboarding{Enabled: false})
+
+	err := usecase.Offboard(context.Background(), domain.OffboardingRequest{UserName: testUserName})
+
+	assert.Error(t, err)
+	mockService.AssertNotCalled(t, "OffboardNamespace")
+}
+
+// ❌ Test `Offboard` (Namespace Service Fails)
+func Test_Offboard_Failure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupOffboardingUsecase(mockService, domain.Offboarding{
+		Enabled: true,
+		Mode:    domain.OffboardingModeDelete,
+	})
+
+	mockService.On(
+		"OffboardNamespace",
+		mock.Anything,
+		userNamespace,
+		"user:"+testUserName,
+		domain.OffboardingModeDelete,
+		time.Duration(0),
+	).
+		Return(interfaces.NamespaceOffboardingResult(""), errors.New("failed to delete namespace"))
+
+	err := usecase.Offboard(context.Background(), domain.OffboardingRequest{UserName: testUserName})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete namespace")
+}
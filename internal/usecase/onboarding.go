@@ -2,50 +2,175 @@ package usecase
 
 import (
 	"context"
+	"log/slog"
+	"strconv"
+	"strings"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"golang.org/x/sync/singleflight"
 )
 
 type onboardingUsecase struct {
 	namespaceService  interfaces.NamespaceService
+	manifestService   interfaces.ManifestService
 	namespace         domain.Namespace
 	quotas            domain.Quotas
+	offboarding       domain.Offboarding
+	rbac              domain.RBAC
+	networkPolicies   domain.NetworkPolicies
+	manifests         domain.Manifests
+	dryRun            bool
 	userContextReader interfaces.UserContextReader
+	onboardings       onboardingGroup // keyed by namespace and caller
+}
+
+// onboardingGroup coalesces concurrent onboardings, see singleflight.Group.
+type onboardingGroup interface {
+	Do(key string, fn func() (any, error)) (any, error, bool)
 }
 
 func NewOnboardingUsecase(
 	namespaceService interfaces.NamespaceService,
+	manifestService interfaces.ManifestService,
 	namespace domain.Namespace,
 	quotas domain.Quotas,
+	offboarding domain.Offboarding,
+	rbac domain.RBAC,
+	networkPolicies domain.NetworkPolicies,
+	manifests domain.Manifests,
+	dryRun bool,
 	userContextReader interfaces.UserContextReader,
 
 ) *onboardingUsecase {
 	return &onboardingUsecase{
 		namespaceService:  namespaceService,
+		manifestService:   manifestService,
 		namespace:         namespace,
 		quotas:            quotas,
+		offboarding:       offboarding,
+		rbac:              rbac,
+		networkPolicies:   networkPolicies,
+		manifests:         manifests,
+		dryRun:            dryRun,
 		userContextReader: userContextReader,
+		onboardings:       new(singleflight.Group),
 	}
 }
 
-func (s *onboardingUsecase) Onboard(ctx context.Context, req domain.OnboardingRequest) error {
-	namespace := s.getNamespace(req)
+func (s *onboardingUsecase) Onboard(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (domain.OnboardingResult, error) {
+	dryRun := req.DryRun || s.dryRun
+	if dryRun {
+		ctx = domain.WithDryRun(ctx)
+	}
 
-	if err := s.createNamespace(ctx, namespace); err != nil {
-		return err
+	namespace, err := s.getNamespace(ctx, req)
+	if err != nil {
+		return domain.OnboardingResult{}, err
 	}
 
-	if err := s.applyQuotas(ctx, namespace, req); err != nil {
-		return err
+	// 🔹 The UI onboards on every login and page reload, often from several tabs at once:
+	// concurrent onboardings of a namespace by the same caller share one execution and its
+	// result. It is not cancelled with the request that started it, as the others wait for it.
+	key := onboardingKey(namespace, req, dryRun)
+	result, err, shared := s.onboardings.Do(key, func() (any, error) {
+		return s.onboard(context.WithoutCancel(ctx), req, namespace, dryRun)
+	})
+	if shared {
+		slog.InfoContext(ctx, "🔹 Shared a concurrent onboarding",
+			slog.String("namespace", namespace),
+		)
+	}
+	if err != nil {
+		return domain.OnboardingResult{}, err
 	}
 
-	return nil
+	return result.(domain.OnboardingResult), nil
 }
 
-func (s *onboardingUsecase) getNamespace(req domain.OnboardingRequest) string {
-	if req.Group != nil {
-		return s.namespace.GroupNamespacePrefix + *req.Group
+func (s *onboardingUsecase) onboard(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+	namespace string,
+	dryRun bool,
+) (domain.OnboardingResult, error) {
+	result := domain.OnboardingResult{
+		Namespace:   namespace,
+		Annotations: s.namespaceAnnotations(ctx, req),
+		Labels:      s.namespaceLabels(ctx, req),
+		DryRun:      dryRun,
+	}
+
+	// 🔹 The UI onboards on every login and page reload: skip namespaces onboarded recently with
+	// the same state, nothing would change
+	if s.namespace.SkipIfOnboardedWithin > 0 {
+		fingerprint, profile, err := s.fingerprint(
+			ctx, req, namespace, result.Annotations, result.Labels,
+		)
+		if err != nil {
+			return domain.OnboardingResult{}, err
+		}
+
+		if s.onboardedRecently(ctx, namespace, fingerprint) {
+			slog.InfoContext(ctx, "🔹 Namespace onboarded recently with the same state, skipping",
+				slog.String("namespace", namespace),
+			)
+			result.QuotaProfile = profile
+			result.Skipped = true
+			return result, nil
+		}
+
+		result.Annotations[domain.FingerprintAnnotation] = fingerprint
+	}
+
+	creation, err := s.createNamespace(ctx, namespace, result.Annotations, result.Labels)
+	if err != nil {
+		return domain.OnboardingResult{}, err
+	}
+
+	// 🔹 A namespace created in dry-run does not exist, so its content cannot be validated
+	if dryRun && creation == interfaces.NamespaceCreated {
+		if s.quotas.Enabled {
+			if _, result.QuotaProfile, err = s.getQuota(ctx, req, namespace); err != nil {
+				return domain.OnboardingResult{}, err
+			}
+		}
+		slog.InfoContext(ctx, "🔹 Dry-run: skipping the content of a new namespace",
+			slog.String("namespace", namespace),
+		)
+		return result, nil
 	}
-	return s.namespace.NamespacePrefix + req.UserName
+
+	if result.QuotaProfile, _, err = s.applyQuotas(ctx, namespace, req); err != nil {
+		return domain.OnboardingResult{}, err
+	}
+
+	if err := s.applyRBAC(ctx, namespace, req); err != nil {
+		return domain.OnboardingResult{}, err
+	}
+
+	if err := s.applyNetworkPolicies(ctx, namespace, req); err != nil {
+		return domain.OnboardingResult{}, err
+	}
+
+	if err := s.applyManifests(ctx, namespace, req); err != nil {
+		return domain.OnboardingResult{}, err
+	}
+
+	return result, nil
+}
+
+// onboardingKey identifies the onboardings that share a result: those of a namespace by the same
+// user, with the same roles and groups, as they select the quota, RBAC and manifests.
+func onboardingKey(namespace string, req domain.OnboardingRequest, dryRun bool) string {
+	return strings.Join([]string{
+		namespace,
+		req.UserName,
+		strings.Join(sorted(req.UserRoles), ","),
+		strings.Join(sorted(req.UserGroups), ","),
+		strconv.FormatBool(dryRun),
+	}, "\x00")
 }
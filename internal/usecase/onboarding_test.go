@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/singleflight"
 )
 
 // ✅ Test `Onboard` Success (Namespace & Quota Applied)
@@ -26,16 +28,128 @@ func Test_Onboard_Success(t *testing.T) {
 
 	mockService.On("ApplyResourceQuotas", mock.Anything, groupNamespace, &quotas.Group).
 		Return(interfaces.QuotaCreated, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, groupNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
 
 	groupName := testGroupName
 	req := domain.OnboardingRequest{Group: &groupName, UserName: testUserName}
-	err := usecase.Onboard(context.Background(), req)
+	_, err := usecase.Onboard(context.Background(), req)
 
 	assert.NoError(t, err)
 	mockService.AssertCalled(t, "CreateNamespace", mock.Anything, groupNamespace)
 	mockService.AssertCalled(t, "ApplyResourceQuotas", mock.Anything, groupNamespace, &quotas.Group)
 }
 
+// joiningGroup signals every caller once it has joined the onboarding of its key.
+type joiningGroup struct {
+	singleflight.Group
+	joined chan struct{}
+}
+
+func (g *joiningGroup) Do(key string, fn func() (any, error)) (any, error, bool) {
+	ch := g.DoChan(key, fn)
+	g.joined <- struct{}{}
+	result := <-ch
+	return result.Val, result.Err, result.Shared
+}
+
+// ✅ Test concurrent `Onboard` calls for a namespace share one execution
+func Test_Onboard_ConcurrentRequestsShared(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupUsecase(mockService, domain.Quotas{Enabled: false}).(*onboardingUsecase)
+
+	group := &joiningGroup{joined: make(chan struct{}, 2)}
+	usecase.onboardings = group
+
+	release := make(chan struct{})
+	mockService.On("CreateNamespace", mock.Anything, defaultNamespace).
+		Run(func(mock.Arguments) { <-release }).
+		Return(interfaces.NamespaceCreated, nil).
+		Once()
+
+	req := domain.OnboardingRequest{UserName: testUserName}
+	results := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := usecase.Onboard(context.Background(), req)
+			results <- err
+		}()
+	}
+
+	// 🔹 The namespace is only created once both calls joined the onboarding in progress
+	for range 2 {
+		select {
+		case <-group.joined:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected both onboardings to join")
+		}
+	}
+	close(release)
+
+	assert.NoError(t, <-results)
+	assert.NoError(t, <-results)
+	mockService.AssertNumberOfCalls(t, "CreateNamespace", 1)
+}
+
+// ✅ Test concurrent `Onboard` calls of different users for a group namespace are not shared
+func Test_Onboard_ConcurrentRequestsOfDifferentUsers(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupUsecase(mockService, domain.Quotas{Enabled: false})
+
+	entered, release := make(chan struct{}, 2), make(chan struct{})
+	mockService.On("CreateNamespace", mock.Anything, groupNamespace).
+		Run(func(mock.Arguments) {
+			entered <- struct{}{}
+			<-release
+		}).
+		Return(interfaces.NamespaceAnnotationsUpdated, nil).
+		Twice()
+
+	groupName := testGroupName
+	results := make(chan error, 2)
+	for _, userName := range []string{"alice", "bob"} {
+		req := domain.OnboardingRequest{
+			Group:      &groupName,
+			UserName:   userName,
+			UserGroups: []string{testGroupName},
+		}
+		go func() {
+			_, err := usecase.Onboard(context.Background(), req)
+			results <- err
+		}()
+	}
+
+	// 🔹 Both onboardings run at once: neither waits for the other
+	for range 2 {
+		select {
+		case <-entered:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected both onboardings to run")
+		}
+	}
+	close(release)
+
+	assert.NoError(t, <-results)
+	assert.NoError(t, <-results)
+	mockService.AssertNumberOfCalls(t, "CreateNamespace", 2)
+}
+
+// ❌ Test `Onboard` of a namespace named as by previous versions, but carrying neither the
+// managed labels nor the identity annotation, is a collision
+func Test_Onboard_UnmarkedLegacyNamespace(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupUsecase(mockService, domain.Quotas{Enabled: false})
+
+	mockService.On("CreateNamespace", mock.Anything, userNamespace).
+		Return(interfaces.NamespaceNotOwned, nil)
+
+	req := domain.OnboardingRequest{UserName: testUserName}
+	_, err := usecase.Onboard(context.Background(), req)
+
+	assert.ErrorIs(t, err, domain.ErrNamespaceCollision)
+	mockService.AssertNotCalled(t, "ApplyResourceQuotas")
+}
+
 // ✅ Test `Onboard` Success (Quotas Disabled)
 func Test_Onboard_QuotasDisabled(t *testing.T) {
 	mockService := new(MockNamespaceService)
@@ -46,7 +160,7 @@ func Test_Onboard_QuotasDisabled(t *testing.T) {
 		Return(interfaces.NamespaceCreated, nil)
 
 	req := domain.OnboardingRequest{Group: nil, UserName: testUserName}
-	err := usecase.Onboard(context.Background(), req)
+	_, err := usecase.Onboard(context.Background(), req)
 
 	assert.NoError(t, err)
 	mockService.AssertCalled(t, "CreateNamespace", mock.Anything, defaultNamespace)
@@ -65,7 +179,7 @@ func Test_Onboard_CreateNamespaceFails(t *testing.T) {
 
 	groupName := testGroupName
 	req := domain.OnboardingRequest{Group: &groupName, UserName: testUserName}
-	err := usecase.Onboard(context.Background(), req)
+	_, err := usecase.Onboard(context.Background(), req)
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -86,7 +200,7 @@ func Test_Onboard_ApplyResourceQuotasFails(t *testing.T) {
 		Return(interfaces.QuotaApplicationResult(""), errors.New("failed to apply quota"))
 
 	req := domain.OnboardingRequest{Group: nil, UserName: testUserName}
-	err := usecase.Onboard(context.Background(), req)
+	_, err := usecase.Onboard(context.Background(), req)
 
 	assert.Error(t, err)
 	mockService.AssertCalled(t, "CreateNamespace", mock.Anything, defaultNamespace)
@@ -110,9 +224,11 @@ func Test_Onboard_NamespaceAlreadyExists(t *testing.T) {
 
 	mockService.On("ApplyResourceQuotas", mock.Anything, defaultNamespace, &quotas.Default).
 		Return(interfaces.QuotaCreated, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, defaultNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
 
 	req := domain.OnboardingRequest{Group: nil, UserName: testUserName}
-	err := usecase.Onboard(context.Background(), req)
+	_, err := usecase.Onboard(context.Background(), req)
 
 	assert.NoError(t, err)
 	mockService.AssertCalled(t, "CreateNamespace", mock.Anything, defaultNamespace)
@@ -132,9 +248,66 @@ func TestGetNamespace(t *testing.T) {
 
 	// Case 1: Group is provided
 	reqWithGroup := domain.OnboardingRequest{Group: &groupName, UserName: testUserName}
-	assert.Equal(t, groupNamespace, usecase.getNamespace(reqWithGroup))
+	namespace, err := usecase.getNamespace(context.Background(), reqWithGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, groupNamespace, namespace)
 
 	// Case 2: No group, only user
 	reqWithoutGroup := domain.OnboardingRequest{Group: nil, UserName: testUserName}
-	assert.Equal(t, userNamespace, usecase.getNamespace(reqWithoutGroup))
+	namespace, err = usecase.getNamespace(context.Background(), reqWithoutGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, userNamespace, namespace)
+}
+
+// ✅ Test `Onboard` Dry-Run (New Namespace)
+func Test_Onboard_DryRunNewNamespace(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{Enabled: true, UserEnabled: true, User: domain.Quota{CPURequest: "2"}}
+	usecase := setupUsecase(mockService, quotas)
+
+	mockService.On(
+		"CreateNamespace",
+		mock.MatchedBy(func(ctx context.Context) bool { return domain.IsDryRun(ctx) }),
+		userNamespace,
+	).Return(interfaces.NamespaceCreated, nil)
+
+	req := domain.OnboardingRequest{UserName: testUserName, DryRun: true}
+	result, err := usecase.Onboard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OnboardingResult{
+		Namespace: userNamespace,
+		Annotations: map[string]string{
+			domain.IdentityAnnotation: "user:" + testUserName,
+			domain.RolesAnnotation:    "",
+		},
+		QuotaProfile: "user",
+		DryRun:       true,
+	}, result)
+	mockService.AssertNotCalled(t, "ApplyResourceQuotas")
+}
+
+// ✅ Test `Onboard` Dry-Run (Existing Namespace)
+func Test_Onboard_DryRunExistingNamespace(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{Enabled: true, Default: domain.Quota{CPURequest: "1"}}
+	usecase := setupPrivateUsecase(mockService, quotas)
+	usecase.dryRun = true
+
+	isDryRun := mock.MatchedBy(func(ctx context.Context) bool { return domain.IsDryRun(ctx) })
+
+	mockService.On("CreateNamespace", isDryRun, userNamespace).
+		Return(interfaces.NamespaceAnnotationsUpdated, nil)
+	mockService.On("ApplyResourceQuotas", isDryRun, userNamespace, &quotas.Default).
+		Return(interfaces.QuotaUpdated, nil)
+	mockService.On("DeleteLimitRange", isDryRun, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
+
+	req := domain.OnboardingRequest{UserName: testUserName}
+	result, err := usecase.Onboard(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun, "Expected the service-wide dry-run mode to apply")
+	assert.Equal(t, "default", result.QuotaProfile)
+	mockService.AssertExpectations(t)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"path"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
@@ -13,15 +14,29 @@ func (s *onboardingUsecase) applyQuotas(
 	ctx context.Context,
 	namespace string,
 	req domain.OnboardingRequest,
-) error {
-	if !s.quotas.Enabled {
+) (string, interfaces.QuotaApplicationResult, error) {
+	removeQuotas := s.quotas.RemovalPolicy == domain.QuotaRemovalPolicyDelete
+
+	if !s.quotas.Enabled && !removeQuotas {
 		slog.WarnContext(ctx, "⚠️ Quotas are disabled, skipping quota application",
 			slog.String("namespace", namespace),
 		)
-		return nil
+		return "", "", nil
 	}
 
-	quotaToApply := s.getQuota(ctx, req, namespace)
+	// ✅ With quotas disabled, an empty quota is applied so that managed quotas get removed
+	quotaToApply, profile := &domain.Quota{}, ""
+	if s.quotas.Enabled {
+		var err error
+		quotaToApply, profile, err = s.getQuota(ctx, req, namespace)
+		if err != nil {
+			return "", "", err
+		}
+	} else {
+		slog.InfoContext(ctx, "🔹 Quotas are disabled, removing managed quotas",
+			slog.String("namespace", namespace),
+		)
+	}
 
 	result, err := s.namespaceService.ApplyResourceQuotas(ctx, namespace, quotaToApply)
 	if err != nil {
@@ -29,7 +44,23 @@ func (s *onboardingUsecase) applyQuotas(
 			slog.String("namespace", namespace),
 			slog.Any("error", err),
 		)
-		return fmt.Errorf("failed to apply quotas to namespace (%s): %w", namespace, err)
+		return "", "", fmt.Errorf("failed to apply quotas to namespace (%s): %w", namespace, err)
+	}
+
+	// 🔹 A profile without limits has no main quota, remove the one left by a previous profile
+	if removeQuotas && !hasLimits(quotaToApply) {
+		result, err = s.namespaceService.DeleteResourceQuota(ctx, namespace)
+		if err != nil {
+			slog.ErrorContext(ctx, "❌ Failed to delete quota",
+				slog.String("namespace", namespace),
+				slog.Any("error", err),
+			)
+			return "", "", fmt.Errorf(
+				"failed to delete quota of namespace (%s): %w",
+				namespace,
+				err,
+			)
+		}
 	}
 
 	switch result {
@@ -49,19 +80,34 @@ func (s *onboardingUsecase) applyQuotas(
 		slog.WarnContext(ctx, "⚠️ Quota ignored due to annotation",
 			slog.String("namespace", namespace),
 		)
+	case interfaces.QuotaPartiallyIgnored:
+		slog.WarnContext(ctx, "⚠️ Quota partially ignored due to annotation",
+			slog.String("namespace", namespace),
+		)
+	case interfaces.QuotaDeleted:
+		slog.InfoContext(ctx, "✅ Deleted resource quota no longer configured",
+			slog.String("namespace", namespace),
+		)
+	}
+
+	if err := s.applyLimitRange(ctx, namespace, quotaToApply); err != nil {
+		return "", "", err
 	}
 
-	return nil
+	return profile, result, nil
 }
 
+// getQuota returns the quota to apply along with the name of its profile: "default", "user",
+// "group" or "roles.<role>" ("roles.<role>+<role>" when role quotas are merged).
 func (s *onboardingUsecase) getQuota(
 	ctx context.Context,
 	req domain.OnboardingRequest,
 	namespace string,
-) *domain.Quota {
+) (*domain.Quota, string, error) {
 	// ✅ If a group is set, check if group quotas are enabled
 	if req.Group != nil {
-		return s.getGroupQuota(ctx, req, namespace)
+		quota, profile := s.getGroupQuota(ctx, req, namespace)
+		return quota, profile, nil
 	}
 
 	// ✅ Otherwise, apply user quota (roles first)
@@ -72,30 +118,45 @@ func (s *onboardingUsecase) getGroupQuota(
 	ctx context.Context,
 	req domain.OnboardingRequest,
 	namespace string,
-) *domain.Quota {
+) (*domain.Quota, string) {
+	for i := range s.quotas.Groups {
+		groupQuota := &s.quotas.Groups[i]
+		if key, ok := matchGroupQuota(groupQuota, *req.Group); ok {
+			slog.InfoContext(ctx, "🔹 Applying group-specific quota",
+				slog.String("namespace", namespace),
+				slog.String("group", *req.Group),
+				slog.String("match", key),
+			)
+			return &groupQuota.Quota, "groups." + key
+		}
+	}
+
 	if s.quotas.GroupEnabled {
 		slog.InfoContext(ctx, "🔹 Applying group quota",
 			slog.String("namespace", namespace),
 			slog.String("group", *req.Group),
 		)
-		return &s.quotas.Group
+		return &s.quotas.Group, "group"
 	}
-	return &s.quotas.Default
+	return &s.quotas.Default, "default"
 }
 
 func (s *onboardingUsecase) getUserQuota(
 	ctx context.Context,
 	req domain.OnboardingRequest,
 	namespace string,
-) *domain.Quota {
-	for _, role := range req.UserRoles {
-		if quota, exists := s.quotas.Roles[role]; exists {
-			slog.InfoContext(ctx, "🔹 Applying role-based user quota",
-				slog.String("namespace", namespace),
-				slog.String("role", role),
-			)
-			return &quota
-		}
+) (*domain.Quota, string, error) {
+	quota, profile, err := s.getRoleQuota(req.UserRoles)
+	if err != nil {
+		return nil, "", err
+	}
+	if quota != nil {
+		slog.InfoContext(ctx, "🔹 Applying role-based user quota",
+			slog.String("namespace", namespace),
+			slog.String("profile", profile),
+			slog.String("strategy", string(s.quotas.RoleMergeStrategy)),
+		)
+		return quota, profile, nil
 	}
 
 	// ✅ Fallback to user quota (if enabled)
@@ -103,12 +164,38 @@ func (s *onboardingUsecase) getUserQuota(
 		slog.InfoContext(ctx, "🔹 Applying user quota",
 			slog.String("namespace", namespace),
 		)
-		return &s.quotas.User
+		return &s.quotas.User, "user", nil
 	}
 
 	// ✅ Fallback to default quota
 	slog.InfoContext(ctx, "🔹 Applying default quota",
 		slog.String("namespace", namespace),
 	)
-	return &s.quotas.Default
+	return &s.quotas.Default, "default", nil
+}
+
+// hasLimits reports whether the quota sets at least one resource of the main quota.
+func hasLimits(quota *domain.Quota) bool {
+	q := *quota
+	for _, field := range quotaFields(&q) {
+		if *field != "" {
+			return true
+		}
+	}
+	return len(quota.Resources) > 0
+}
+
+// matchGroupQuota reports whether the group quota applies to group, along with the name, pattern
+// or regex it matched.
+func matchGroupQuota(groupQuota *domain.GroupQuota, group string) (string, bool) {
+	switch {
+	case groupQuota.Name != "":
+		return groupQuota.Name, groupQuota.Name == group
+	case groupQuota.Pattern != "":
+		matched, _ := path.Match(groupQuota.Pattern, group) // validated at startup
+		return groupQuota.Pattern, matched
+	case groupQuota.Regex != nil:
+		return groupQuota.Regex.String(), groupQuota.Regex.MatchString(group)
+	}
+	return "", false
 }
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// getRoleQuota returns the quota of the roles of the user according to the role merge strategy,
+// along with its profile name. It returns a nil quota when no role of the user has a quota.
+func (s *onboardingUsecase) getRoleQuota(userRoles []string) (*domain.Quota, string, error) {
+	switch s.quotas.RoleMergeStrategy {
+	case domain.RoleMergeStrategyPriority:
+		for _, role := range s.quotas.RolePriority {
+			if !slices.Contains(userRoles, role) {
+				continue
+			}
+			if quota, exists := s.quotas.Roles[role]; exists {
+				return &quota, "roles." + role, nil
+			}
+		}
+		return nil, "", nil
+
+	case domain.RoleMergeStrategyMax, domain.RoleMergeStrategySum:
+		var roles []string
+		for _, role := range userRoles {
+			if _, exists := s.quotas.Roles[role]; exists && !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+		if len(roles) == 0 {
+			return nil, "", nil
+		}
+		slices.Sort(roles)
+
+		quotas := make([]domain.Quota, 0, len(roles))
+		for _, role := range roles {
+			quotas = append(quotas, s.quotas.Roles[role])
+		}
+
+		merged, err := mergeQuotas(quotas, s.quotas.RoleMergeStrategy)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to merge quotas of roles %v: %w", roles, err)
+		}
+		return &merged, "roles." + strings.Join(roles, "+"), nil
+
+	default:
+		for _, role := range userRoles {
+			if quota, exists := s.quotas.Roles[role]; exists {
+				return &quota, "roles." + role, nil
+			}
+		}
+		return nil, "", nil
+	}
+}
+
+// mergeQuotas merges quotas field by field, taking the maximum or the sum of each resource.
+// An empty resource is not limited, so it stays empty in the merged quota. LimitRange values
+// follow the same rule but are never summed: the maximum is taken.
+func mergeQuotas(quotas []domain.Quota, strategy domain.RoleMergeStrategy) (domain.Quota, error) {
+	var merged domain.Quota
+	var err error
+
+	for i, field := range quotaFields(&merged) {
+		values := make([]string, 0, len(quotas))
+		for j := range quotas {
+			values = append(values, *quotaFields(&quotas[j])[i])
+		}
+
+		value, err := mergeQuantities(values, strategy)
+		if err != nil {
+			return domain.Quota{}, err
+		}
+		*field = value
+	}
+
+	// 🔹 Other resources are merged like typed ones: one left out by a quota stays unlimited
+	resources := make([]map[string]string, 0, len(quotas))
+	for _, quota := range quotas {
+		resources = append(resources, quota.Resources)
+	}
+	merged.Resources, err = mergeResourceMaps(resources, strategy)
+	if err != nil {
+		return domain.Quota{}, err
+	}
+
+	// 🔹 Likewise, a scoped quota missing from one of the quotas does not limit anything
+	for _, scoped := range quotas[0].Scoped {
+		hard := []map[string]string{scoped.Hard}
+		for _, quota := range quotas[1:] {
+			i := slices.IndexFunc(quota.Scoped, func(q domain.ScopedQuota) bool {
+				return q.Name == scoped.Name
+			})
+			if i < 0 {
+				hard = nil
+				break
+			}
+			hard = append(hard, quota.Scoped[i].Hard)
+		}
+		if hard == nil {
+			continue
+		}
+
+		scoped.Hard, err = mergeResourceMaps(hard, strategy)
+		if err != nil {
+			return domain.Quota{}, err
+		}
+		if len(scoped.Hard) > 0 {
+			merged.Scoped = append(merged.Scoped, scoped)
+		}
+	}
+
+	for i, field := range limitRangeFields(&merged.LimitRange) {
+		values := make([]string, 0, len(quotas))
+		for j := range quotas {
+			values = append(values, *limitRangeFields(&quotas[j].LimitRange)[i])
+		}
+
+		value, err := mergeQuantities(values, domain.RoleMergeStrategyMax)
+		if err != nil {
+			return domain.Quota{}, err
+		}
+		*field = value
+	}
+
+	return merged, nil
+}
+
+// mergeResourceMaps merges the resources set in every map, the others are not limited.
+func mergeResourceMaps(
+	resources []map[string]string,
+	strategy domain.RoleMergeStrategy,
+) (map[string]string, error) {
+	var merged map[string]string
+
+	for name, value := range resources[0] {
+		values := []string{value}
+		for _, other := range resources[1:] {
+			values = append(values, other[name])
+		}
+
+		value, err := mergeQuantities(values, strategy)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			continue
+		}
+		if merged == nil {
+			merged = make(map[string]string)
+		}
+		merged[name] = value
+	}
+
+	return merged, nil
+}
+
+func quotaFields(q *domain.Quota) []*string {
+	return []*string{
+		&q.MemoryRequest,
+		&q.CPURequest,
+		&q.MemoryLimit,
+		&q.CPULimit,
+		&q.StorageRequest,
+		&q.MaxPods,
+		&q.EphemeralStorageRequest,
+		&q.EphemeralStorageLimit,
+		&q.GPURequest,
+		&q.GPULimit,
+	}
+}
+
+func limitRangeFields(l *domain.LimitRange) []*string {
+	return []*string{
+		&l.DefaultCPU,
+		&l.DefaultMemory,
+		&l.DefaultRequestCPU,
+		&l.DefaultRequestMemory,
+		&l.MaxCPU,
+		&l.MaxMemory,
+	}
+}
+
+// mergeQuantities returns the maximum or the sum of quantities, or "" if one of them is empty.
+func mergeQuantities(values []string, strategy domain.RoleMergeStrategy) (string, error) {
+	if len(values) == 0 || slices.Contains(values, "") {
+		return "", nil
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+
+	var result resource.Quantity
+	for i, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid quantity %q: %w", value, err)
+		}
+
+		switch {
+		case i == 0:
+			result = quantity
+		case strategy == domain.RoleMergeStrategySum:
+			result.Add(quantity)
+		case quantity.Cmp(result) > 0:
+			result = quantity
+		}
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRoleQuotas(strategy domain.RoleMergeStrategy, priority ...string) *onboardingUsecase {
+	return setupPrivateUsecase(new(MockNamespaceService), domain.Quotas{
+		Enabled: true,
+		Default: domain.Quota{MemoryRequest: "10Gi"},
+		Roles: map[string]domain.Quota{
+			"admin": {
+				MemoryRequest: "16Gi",
+				CPURequest:    "4",
+				MaxPods:       "10",
+				LimitRange:    domain.LimitRange{DefaultCPU: "1", MaxCPU: "4"},
+			},
+			"developer": {
+				MemoryRequest: "8Gi",
+				CPURequest:    "500m",
+				LimitRange:    domain.LimitRange{DefaultCPU: "2"},
+			},
+		},
+		RoleMergeStrategy: strategy,
+		RolePriority:      priority,
+	})
+}
+
+func TestGetQuota_RolePriority(t *testing.T) {
+	usecase := setupRoleQuotas(domain.RoleMergeStrategyPriority, "admin", "developer")
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"developer", "admin"}, // ✅ token order does not matter
+	}
+
+	quota, profile, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "roles.admin", profile)
+	assert.Equal(t, "16Gi", quota.MemoryRequest)
+}
+
+func TestGetQuota_RolePriority_RoleNotListed(t *testing.T) {
+	usecase := setupRoleQuotas(domain.RoleMergeStrategyPriority, "admin")
+
+	req := domain.OnboardingRequest{UserName: testUserName, UserRoles: []string{"developer"}}
+
+	quota, profile, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "default", profile)
+	assert.Equal(t, "10Gi", quota.MemoryRequest)
+}
+
+func TestGetQuota_RoleMax(t *testing.T) {
+	usecase := setupRoleQuotas(domain.RoleMergeStrategyMax)
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"developer", "admin", "viewer"},
+	}
+
+	quota, profile, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "roles.admin+developer", profile)
+	assert.Equal(t, "16Gi", quota.MemoryRequest)
+	assert.Equal(t, "4", quota.CPURequest)
+	assert.Empty(t, quota.MaxPods, "Expected pods to stay unlimited, developer does not limit them")
+	// ✅ developer does not cap the CPU of containers, so neither does the merged LimitRange
+	assert.Equal(t, domain.LimitRange{DefaultCPU: "2"}, quota.LimitRange)
+}
+
+func TestGetQuota_RoleSum(t *testing.T) {
+	usecase := setupRoleQuotas(domain.RoleMergeStrategySum)
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"admin", "developer"},
+	}
+
+	quota, profile, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "roles.admin+developer", profile)
+	assert.Equal(t, "24Gi", quota.MemoryRequest)
+	assert.Equal(t, "4500m", quota.CPURequest)
+	// ✅ LimitRange values are not summed
+	assert.Equal(t, "2", quota.LimitRange.DefaultCPU)
+}
+
+func TestGetQuota_RoleSum_SingleRole(t *testing.T) {
+	usecase := setupRoleQuotas(domain.RoleMergeStrategySum)
+
+	req := domain.OnboardingRequest{UserName: testUserName, UserRoles: []string{"developer"}}
+
+	quota, profile, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "roles.developer", profile)
+	assert.Equal(t, "500m", quota.CPURequest)
+}
+
+func TestGetQuota_RoleSum_InvalidQuantity(t *testing.T) {
+	usecase := setupRoleQuotas(domain.RoleMergeStrategySum)
+	usecase.quotas.Roles["developer"] = domain.Quota{MemoryRequest: "lots"}
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"admin", "developer"},
+	}
+
+	_, _, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid quantity \"lots\"")
+}
+
+func TestGetQuota_RoleMax_Resources(t *testing.T) {
+	usecase := setupRoleQuotas(domain.RoleMergeStrategyMax)
+	usecase.quotas.Roles["admin"] = domain.Quota{
+		Resources: map[string]string{"amd.com/gpu": "1", "persistentvolumeclaims": "10"},
+	}
+	usecase.quotas.Roles["developer"] = domain.Quota{
+		Resources: map[string]string{"amd.com/gpu": "2"},
+	}
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"admin", "developer"},
+	}
+
+	quota, _, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.NoError(t, err)
+	// ✅ developer does not limit persistentvolumeclaims, so they stay unlimited
+	assert.Equal(t, map[string]string{"amd.com/gpu": "2"}, quota.Resources)
+}
+
+func TestGetQuota_RoleSum_Scoped(t *testing.T) {
+	gpu := func(gpus string) domain.ScopedQuota {
+		return domain.ScopedQuota{
+			Name:   "gpu-high",
+			Scopes: []string{"NotBestEffort"},
+			Hard:   map[string]string{"requests.nvidia.com/gpu": gpus},
+		}
+	}
+	usecase := setupRoleQuotas(domain.RoleMergeStrategySum)
+	usecase.quotas.Roles["admin"] = domain.Quota{
+		Scoped: []domain.ScopedQuota{
+			gpu("2"),
+			{Name: "admin-only", Hard: map[string]string{"pods": "1"}},
+		},
+	}
+	usecase.quotas.Roles["developer"] = domain.Quota{
+		Scoped: []domain.ScopedQuota{gpu("1")},
+	}
+
+	req := domain.OnboardingRequest{
+		UserName:  testUserName,
+		UserRoles: []string{"admin", "developer"},
+	}
+
+	quota, _, err := usecase.getQuota(context.Background(), req, userNamespace)
+
+	assert.NoError(t, err)
+	// ✅ admin-only does not limit developers, so it is left out
+	assert.Equal(t, []domain.ScopedQuota{gpu("3")}, quota.Scoped)
+}
+
+func TestMergeQuotas_LimitRange(t *testing.T) {
+	tests := map[string]struct {
+		limitRanges []domain.LimitRange
+		strategy    domain.RoleMergeStrategy
+		expected    domain.LimitRange
+	}{
+		"all set": {
+			limitRanges: []domain.LimitRange{
+				{MaxCPU: "2", MaxMemory: "4Gi"},
+				{MaxCPU: "4", MaxMemory: "2Gi"},
+			},
+			strategy: domain.RoleMergeStrategyMax,
+			expected: domain.LimitRange{MaxCPU: "4", MaxMemory: "4Gi"},
+		},
+		"empty is unlimited": {
+			limitRanges: []domain.LimitRange{
+				{MaxCPU: "2", MaxMemory: "4Gi"},
+				{MaxCPU: "4"},
+			},
+			strategy: domain.RoleMergeStrategyMax,
+			expected: domain.LimitRange{MaxCPU: "4"},
+		},
+		"empty wins whatever its position": {
+			limitRanges: []domain.LimitRange{
+				{DefaultCPU: "1"},
+				{DefaultCPU: "500m", DefaultMemory: "1Gi"},
+			},
+			strategy: domain.RoleMergeStrategyMax,
+			expected: domain.LimitRange{DefaultCPU: "1"},
+		},
+		"sum takes the maximum": {
+			limitRanges: []domain.LimitRange{
+				{DefaultRequestCPU: "1", MaxMemory: "8Gi"},
+				{DefaultRequestCPU: "2", MaxMemory: ""},
+			},
+			strategy: domain.RoleMergeStrategySum,
+			expected: domain.LimitRange{DefaultRequestCPU: "2"},
+		},
+		"none set": {
+			limitRanges: []domain.LimitRange{{}, {}},
+			strategy:    domain.RoleMergeStrategySum,
+			expected:    domain.LimitRange{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			quotas := make([]domain.Quota, 0, len(tt.limitRanges))
+			for _, limitRange := range tt.limitRanges {
+				quotas = append(quotas, domain.Quota{LimitRange: limitRange})
+			}
+
+			merged, err := mergeQuotas(quotas, tt.strategy)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, merged.LimitRange)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"regexp"
 	"testing"
 
 	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
@@ -21,8 +22,10 @@ func TestApplyQuotas_Success(t *testing.T) {
 
 	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
 		Return(interfaces.QuotaCreated, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
 
-	err := usecase.applyQuotas(
+	_, _, err := usecase.applyQuotas(
 		context.Background(),
 		userNamespace,
 		domain.OnboardingRequest{UserName: testUserName},
@@ -48,8 +51,10 @@ func TestApplyQuotas_AlreadyUpToDate(t *testing.T) {
 
 	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
 		Return(interfaces.QuotaUnchanged, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
 
-	err := usecase.applyQuotas(
+	_, _, err := usecase.applyQuotas(
 		context.Background(),
 		userNamespace,
 		domain.OnboardingRequest{UserName: testUserName},
@@ -70,7 +75,7 @@ func TestApplyQuotas_QuotasDisabled(t *testing.T) {
 	quotas := domain.Quotas{Enabled: false}
 	usecase := setupPrivateUsecase(mockService, quotas)
 
-	err := usecase.applyQuotas(
+	_, _, err := usecase.applyQuotas(
 		context.Background(),
 		userNamespace,
 		domain.OnboardingRequest{UserName: testUserName},
@@ -80,6 +85,97 @@ func TestApplyQuotas_QuotasDisabled(t *testing.T) {
 	mockService.AssertNotCalled(t, "ApplyResourceQuotas")
 }
 
+func TestApplyQuotas_QuotasDisabled_RemovalPolicyDelete(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{Enabled: false, RemovalPolicy: domain.QuotaRemovalPolicyDelete}
+	usecase := setupPrivateUsecase(mockService, quotas)
+
+	// ✅ An empty quota prunes the scoped quotas, then the main quota is deleted
+	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &domain.Quota{}).
+		Return(interfaces.QuotaUnchanged, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
+	mockService.On("DeleteResourceQuota", mock.Anything, userNamespace).
+		Return(interfaces.QuotaDeleted, nil)
+
+	profile, _, err := usecase.applyQuotas(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	assert.Empty(t, profile)
+	mockService.AssertExpectations(t)
+}
+
+func TestApplyQuotas_NoLimits_RemovalPolicyDelete(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{
+		Enabled:       true,
+		Default:       domain.Quota{LimitRange: domain.LimitRange{DefaultCPU: "1"}},
+		RemovalPolicy: domain.QuotaRemovalPolicyDelete,
+	}
+	usecase := setupPrivateUsecase(mockService, quotas)
+
+	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
+		Return(interfaces.QuotaUnchanged, nil)
+	mockService.On("DeleteResourceQuota", mock.Anything, userNamespace).
+		Return(interfaces.QuotaDeleted, nil)
+	mockService.On("ApplyLimitRange", mock.Anything, userNamespace, &quotas.Default.LimitRange).
+		Return(interfaces.LimitRangeUnchanged, nil)
+
+	profile, _, err := usecase.applyQuotas(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "default", profile)
+	mockService.AssertExpectations(t)
+}
+
+func TestApplyQuotas_NoLimits_RemovalPolicyKeep(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{Enabled: true, RemovalPolicy: domain.QuotaRemovalPolicyKeep}
+	usecase := setupPrivateUsecase(mockService, quotas)
+
+	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
+		Return(interfaces.QuotaUnchanged, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
+
+	_, _, err := usecase.applyQuotas(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "DeleteResourceQuota", mock.Anything, mock.Anything)
+}
+
+func TestApplyQuotas_DeleteError(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{Enabled: false, RemovalPolicy: domain.QuotaRemovalPolicyDelete}
+	usecase := setupPrivateUsecase(mockService, quotas)
+
+	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &domain.Quota{}).
+		Return(interfaces.QuotaUnchanged, nil)
+	mockService.On("DeleteResourceQuota", mock.Anything, userNamespace).
+		Return(interfaces.QuotaApplicationResult(""), errors.New("forbidden"))
+
+	_, _, err := usecase.applyQuotas(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to delete quota")
+}
+
 func TestApplyQuotas_QuotaUpdated(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
@@ -90,8 +186,10 @@ func TestApplyQuotas_QuotaUpdated(t *testing.T) {
 
 	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
 		Return(interfaces.QuotaUpdated, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
 
-	err := usecase.applyQuotas(
+	_, _, err := usecase.applyQuotas(
 		context.Background(),
 		userNamespace,
 		domain.OnboardingRequest{UserName: testUserName},
@@ -117,8 +215,10 @@ func TestApplyQuotas_QuotaIgnored(t *testing.T) {
 
 	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
 		Return(interfaces.QuotaIgnored, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, userNamespace).
+		Return(interfaces.LimitRangeUnchanged, nil)
 
-	err := usecase.applyQuotas(
+	_, _, err := usecase.applyQuotas(
 		context.Background(),
 		userNamespace,
 		domain.OnboardingRequest{UserName: testUserName},
@@ -143,7 +243,7 @@ func TestApplyQuotas_Failure(t *testing.T) {
 
 	mockService.On("ApplyResourceQuotas", mock.Anything, userNamespace, &quotas.Default).
 		Return(interfaces.QuotaApplicationResult(""), errors.New("failed to apply quotas"))
-	err := usecase.applyQuotas(
+	_, _, err := usecase.applyQuotas(
 		context.Background(),
 		userNamespace,
 		domain.OnboardingRequest{UserName: testUserName},
@@ -171,11 +271,46 @@ func TestGetQuota_GroupQuota(t *testing.T) {
 	groupName := testGroupName
 	req := domain.OnboardingRequest{Group: &groupName, UserName: testUserName}
 
-	quota := usecase.getQuota(context.Background(), req, groupNamespace)
+	quota, _, _ := usecase.getQuota(context.Background(), req, groupNamespace)
 
 	assert.Equal(t, &quotas.Group, quota)
 }
 
+func TestGetGroupQuota_GroupSpecificQuota(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	quotas := domain.Quotas{
+		Enabled:      true,
+		GroupEnabled: true,
+		Group:        domain.Quota{MemoryRequest: "12Gi"},
+		Groups: []domain.GroupQuota{
+			{Name: "research-lab", Quota: domain.Quota{MemoryRequest: "64Gi"}},
+			{Pattern: "research-*", Quota: domain.Quota{MemoryRequest: "32Gi"}},
+			{
+				Regex: regexp.MustCompile("^students-"),
+				Quota: domain.Quota{MemoryRequest: "4Gi"},
+			},
+		},
+	}
+	usecase := setupPrivateUsecase(mockService, quotas)
+
+	for group, expected := range map[string]struct {
+		memory  string
+		profile string
+	}{
+		"research-lab":   {"64Gi", "groups.research-lab"},
+		"research-other": {"32Gi", "groups.research-*"},
+		"students-2025":  {"4Gi", "groups.^students-"},
+		"other":          {"12Gi", "group"},
+	} {
+		req := domain.OnboardingRequest{UserName: testUserName, Group: &group}
+
+		quota, profile := usecase.getGroupQuota(context.Background(), req, groupNamespace)
+
+		assert.Equal(t, expected.memory, quota.MemoryRequest, group)
+		assert.Equal(t, expected.profile, profile, group)
+	}
+}
+
 func TestGetGroupQuota_FallbackToDefault(t *testing.T) {
 	mockService := new(MockNamespaceService)
 	quotas := domain.Quotas{
@@ -189,7 +324,7 @@ func TestGetGroupQuota_FallbackToDefault(t *testing.T) {
 	groupName := testGroupName
 	req := domain.OnboardingRequest{UserName: testUserName, Group: &groupName}
 
-	quota := usecase.getGroupQuota(context.Background(), req, userNamespace)
+	quota, _ := usecase.getGroupQuota(context.Background(), req, userNamespace)
 
 	// ✅ Expected: Fallback to `quotas.Default`
 	assert.Equal(
@@ -211,7 +346,7 @@ func TestGetQuota_UserQuota(t *testing.T) {
 
 	req := domain.OnboardingRequest{Group: nil, UserName: testUserName}
 
-	quota := usecase.getQuota(context.Background(), req, userNamespace)
+	quota, _, _ := usecase.getQuota(context.Background(), req, userNamespace)
 
 	assert.Equal(t, &quotas.User, quota)
 }
@@ -226,7 +361,7 @@ func TestGetQuota_DefaultQuota(t *testing.T) {
 
 	req := domain.OnboardingRequest{Group: nil, UserName: testUserName}
 
-	quota := usecase.getQuota(context.Background(), req, userNamespace)
+	quota, _, _ := usecase.getQuota(context.Background(), req, userNamespace)
 
 	assert.Equal(t, &quotas.Default, quota)
 }
@@ -246,7 +381,7 @@ func TestGetQuota_RoleQuota(t *testing.T) {
 		UserRoles: []string{"admin"}, // ✅ Only one role, should be used
 	}
 
-	quota := usecase.getQuota(context.Background(), req, userNamespace)
+	quota, _, _ := usecase.getQuota(context.Background(), req, userNamespace)
 
 	expectedQuota := quotas.Roles["admin"]
 	assert.Equal(t, &expectedQuota, quota, "Expected 'admin' role quota")
@@ -268,7 +403,7 @@ func TestGetQuota_RoleQuota_AppliesFirstMatch(t *testing.T) {
 		UserRoles: []string{"developer", "admin"}, // ✅ "developer" should be used
 	}
 
-	quota := usecase.getQuota(context.Background(), req, userNamespace)
+	quota, _, _ := usecase.getQuota(context.Background(), req, userNamespace)
 
 	expectedQuota := quotas.Roles["developer"] // ✅ Copy value before taking address
 	assert.Equal(t, &expectedQuota, quota, "Expected the first matching role's quota")
@@ -292,7 +427,7 @@ func TestGetQuota_UserQuota_WhenNoRoleMatches(t *testing.T) {
 		UserRoles: []string{"nonexistent-role"}, // ❌ Role is not in the quota map
 	}
 
-	quota := usecase.getQuota(context.Background(), req, userNamespace)
+	quota, _, _ := usecase.getQuota(context.Background(), req, userNamespace)
 
 	expectedQuota := quotas.User
 	assert.Equal(t, &expectedQuota, quota, "Expected fallback to user quota when no role matches")
@@ -312,7 +447,7 @@ func TestGetQuota_DefaultQuota_WhenNoRoleAndUserQuotaDisabled(t *testing.T) {
 		UserRoles: []string{}, // ✅ No roles provided
 	}
 
-	quota := usecase.getQuota(context.Background(), req, userNamespace)
+	quota, _, _ := usecase.getQuota(context.Background(), req, userNamespace)
 
 	expectedQuota := quotas.Default
 	assert.Equal(t, &expectedQuota, quota, "Expected default quota when no role/user quota applies")
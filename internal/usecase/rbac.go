@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+)
+
+// groupMemberLabel identifies the RoleBindings granted to a given member of a group namespace.
+const groupMemberLabel = "onyxia.sh/rbac-member"
+
+func (s *onboardingUsecase) applyRBAC(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	if req.Group == nil {
+		return s.applyUserRoleBinding(ctx, namespace, req)
+	}
+
+	if err := s.applyGroupRoleBinding(ctx, namespace, req); err != nil {
+		return err
+	}
+
+	_, err := s.pruneGroupMemberRoleBindings(ctx, namespace)
+	return err
+}
+
+func (s *onboardingUsecase) applyUserRoleBinding(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	if !s.rbac.Enabled {
+		return nil
+	}
+
+	roleBinding := &domain.RoleBinding{
+		Name:        s.rbac.RoleBindingName,
+		ClusterRole: s.rbac.ClusterRole,
+		Subjects: []domain.Subject{
+			{Kind: domain.SubjectKindUser, Name: s.rbac.UsernamePrefix + req.UserName},
+		},
+	}
+
+	return s.applyRoleBinding(ctx, namespace, roleBinding)
+}
+
+func (s *onboardingUsecase) applyGroupRoleBinding(
+	ctx context.Context,
+	namespace string,
+	req domain.OnboardingRequest,
+) error {
+	groups := s.rbac.Groups
+	if !groups.Enabled {
+		return nil
+	}
+
+	clusterRole := groups.ClusterRole
+	if role, ok := groups.Roles[*req.Group]; ok {
+		clusterRole = role
+	}
+
+	var roleBinding *domain.RoleBinding
+
+	switch groups.Mode {
+	case domain.GroupRBACModeMembers:
+		member := s.rbac.UsernamePrefix + req.UserName
+		roleBinding = &domain.RoleBinding{
+			Name:        groups.RoleBindingName + "-" + memberHash(member),
+			ClusterRole: clusterRole,
+			Subjects:    []domain.Subject{{Kind: domain.SubjectKindUser, Name: member}},
+			Labels:      map[string]string{groupMemberLabel: memberHash(member)},
+			// 🔹 Truncated to the day, so that the RoleBinding is updated at most once a day
+			Annotations: map[string]string{
+				domain.MemberSeenAtAnnotation: time.Now().UTC().
+					Truncate(24 * time.Hour).
+					Format(time.RFC3339),
+			},
+		}
+	default:
+		roleBinding = &domain.RoleBinding{
+			Name:        groups.RoleBindingName,
+			ClusterRole: clusterRole,
+			Subjects: []domain.Subject{
+				{Kind: domain.SubjectKindGroup, Name: groups.GroupPrefix + *req.Group},
+			},
+		}
+	}
+
+	return s.applyRoleBinding(ctx, namespace, roleBinding)
+}
+
+// pruneGroupMemberRoleBindings removes the member RoleBindings of a group namespace whose member
+// has not onboarded into it for MemberExpiry, e.g. as they left the group. It returns how many
+// were removed.
+func (s *onboardingUsecase) pruneGroupMemberRoleBindings(
+	ctx context.Context,
+	namespace string,
+) (int, error) {
+	groups := s.rbac.Groups
+	if !groups.Enabled || groups.Mode != domain.GroupRBACModeMembers || groups.MemberExpiry <= 0 {
+		return 0, nil
+	}
+
+	pruned, err := s.namespaceService.PruneRoleBindings(
+		ctx,
+		namespace,
+		groupMemberLabel,
+		time.Now().Add(-groups.MemberExpiry),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to prune group member role bindings",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return 0, fmt.Errorf(
+			"failed to prune group member role bindings of namespace (%s): %w",
+			namespace,
+			err,
+		)
+	}
+
+	if pruned > 0 {
+		slog.InfoContext(ctx, "✅ Pruned group member role bindings",
+			slog.String("namespace", namespace),
+			slog.Int("count", pruned),
+		)
+	}
+
+	return pruned, nil
+}
+
+func (s *onboardingUsecase) applyRoleBinding(
+	ctx context.Context,
+	namespace string,
+	roleBinding *domain.RoleBinding,
+) error {
+	result, err := s.namespaceService.ApplyRoleBinding(ctx, namespace, roleBinding)
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to apply role binding",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return fmt.Errorf("failed to apply role binding to namespace (%s): %w", namespace, err)
+	}
+
+	logRoleBindingResult(ctx, namespace, roleBinding, result)
+
+	return nil
+}
+
+// memberHash turns a subject name into a value usable in a label and in an object name.
+func memberHash(member string) string {
+	sum := sha256.Sum256([]byte(member))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func logRoleBindingResult(
+	ctx context.Context,
+	namespace string,
+	roleBinding *domain.RoleBinding,
+	result interfaces.RoleBindingApplicationResult,
+) {
+	switch result {
+	case interfaces.RoleBindingCreated:
+		slog.InfoContext(ctx, "✅ Created role binding",
+			slog.String("namespace", namespace),
+			slog.String("roleBinding", roleBinding.Name),
+			slog.String("clusterRole", roleBinding.ClusterRole),
+		)
+	case interfaces.RoleBindingUpdated:
+		slog.InfoContext(ctx, "✅ Updated role binding",
+			slog.String("namespace", namespace),
+			slog.String("roleBinding", roleBinding.Name),
+			slog.String("clusterRole", roleBinding.ClusterRole),
+		)
+	case interfaces.RoleBindingUnchanged:
+		slog.InfoContext(ctx, "Role binding is already up-to-date",
+			slog.String("namespace", namespace),
+			slog.String("roleBinding", roleBinding.Name),
+		)
+	case interfaces.RoleBindingIgnored:
+		slog.WarnContext(ctx, "⚠️ Role binding ignored due to annotation",
+			slog.String("namespace", namespace),
+			slog.String("roleBinding", roleBinding.Name),
+		)
+	}
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupRBACUsecase(mockService *MockNamespaceService, rbac domain.RBAC) *onboardingUsecase {
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.rbac = rbac
+	return usecase
+}
+
+func TestApplyRBAC_Success(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{
+		Enabled:         true,
+		RoleBindingName: "onyxia-user",
+		ClusterRole:     "admin",
+		UsernamePrefix:  "oidc:",
+	})
+
+	expected := &domain.RoleBinding{
+		Name:        "onyxia-user",
+		ClusterRole: "admin",
+		Subjects: []domain.Subject{
+			{Kind: domain.SubjectKindUser, Name: "oidc:" + testUserName},
+		},
+	}
+
+	mockService.On("ApplyRoleBinding", mock.Anything, userNamespace, expected).
+		Return(interfaces.RoleBindingCreated, nil)
+
+	err := usecase.applyRBAC(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyRoleBinding", mock.Anything, userNamespace, expected)
+}
+
+func TestApplyRBAC_Disabled(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{Enabled: false})
+
+	err := usecase.applyRBAC(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "ApplyRoleBinding")
+}
+
+func TestApplyRBAC_SkippedForGroup(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{Enabled: true, ClusterRole: "admin"})
+
+	groupName := testGroupName
+	err := usecase.applyRBAC(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "ApplyRoleBinding")
+}
+
+func TestApplyRBAC_Failure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{
+		Enabled:         true,
+		RoleBindingName: "onyxia-user",
+		ClusterRole:     "admin",
+	})
+
+	mockService.On("ApplyRoleBinding", mock.Anything, userNamespace, mock.Anything).
+		Return(interfaces.RoleBindingApplicationResult(""), errors.New("forbidden"))
+
+	err := usecase.applyRBAC(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to apply role binding")
+}
+
+func TestApplyRBAC_GroupMode(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{
+		Groups: domain.GroupRBAC{
+			Enabled:         true,
+			Mode:            domain.GroupRBACModeGroup,
+			RoleBindingName: "onyxia-group",
+			ClusterRole:     "edit",
+			GroupPrefix:     "oidc:",
+		},
+	})
+
+	expected := &domain.RoleBinding{
+		Name:        "onyxia-group",
+		ClusterRole: "edit",
+		Subjects: []domain.Subject{
+			{Kind: domain.SubjectKindGroup, Name: "oidc:" + testGroupName},
+		},
+	}
+
+	mockService.On("ApplyRoleBinding", mock.Anything, groupNamespace, expected).
+		Return(interfaces.RoleBindingCreated, nil)
+
+	groupName := testGroupName
+	err := usecase.applyRBAC(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(t, "ApplyRoleBinding", mock.Anything, groupNamespace, expected)
+	mockService.AssertNotCalled(t, "PruneRoleBindings")
+}
+
+func TestApplyRBAC_GroupRoleOverride(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{
+		Groups: domain.GroupRBAC{
+			Enabled:         true,
+			Mode:            domain.GroupRBACModeGroup,
+			RoleBindingName: "onyxia-group",
+			ClusterRole:     "edit",
+			Roles:           map[string]string{testGroupName: "view"},
+		},
+	})
+
+	mockService.On("ApplyRoleBinding", mock.Anything, groupNamespace, mock.Anything).
+		Return(interfaces.RoleBindingCreated, nil)
+
+	groupName := testGroupName
+	err := usecase.applyRBAC(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertCalled(
+		t,
+		"ApplyRoleBinding",
+		mock.Anything,
+		groupNamespace,
+		mock.MatchedBy(func(rb *domain.RoleBinding) bool { return rb.ClusterRole == "view" }),
+	)
+}
+
+func TestApplyRBAC_MembersMode(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{
+		UsernamePrefix: "oidc:",
+		Groups: domain.GroupRBAC{
+			Enabled:         true,
+			Mode:            domain.GroupRBACModeMembers,
+			RoleBindingName: "onyxia-member",
+			ClusterRole:     "edit",
+			MemberExpiry:    24 * time.Hour,
+		},
+	})
+
+	hash := memberHash("oidc:" + testUserName)
+	expected := mock.MatchedBy(func(rb *domain.RoleBinding) bool {
+		seenAt, err := time.Parse(time.RFC3339, rb.Annotations[domain.MemberSeenAtAnnotation])
+		return err == nil && time.Since(seenAt) < 24*time.Hour &&
+			rb.Name == "onyxia-member-"+hash &&
+			rb.ClusterRole == "edit" &&
+			assert.ObjectsAreEqual(rb.Subjects, []domain.Subject{
+				{Kind: domain.SubjectKindUser, Name: "oidc:" + testUserName},
+			}) &&
+			rb.Labels[groupMemberLabel] == hash
+	})
+	// 🔹 Members not seen for MemberExpiry are pruned from the onboarded namespace only
+	expiry := mock.MatchedBy(func(seenBefore time.Time) bool {
+		return time.Since(seenBefore).Round(time.Minute) == 24*time.Hour
+	})
+
+	mockService.On("ApplyRoleBinding", mock.Anything, groupNamespace, expected).
+		Return(interfaces.RoleBindingCreated, nil)
+	mockService.On("PruneRoleBindings", mock.Anything, groupNamespace, groupMemberLabel, expiry).
+		Return(1, nil)
+
+	groupName := testGroupName
+	err := usecase.applyRBAC(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{
+			Group:      &groupName,
+			UserName:   testUserName,
+			UserGroups: []string{testGroupName},
+		},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+// ✅ Test the onboarding of a user namespace does not prune member RoleBindings
+func TestApplyRBAC_MembersModeUserNamespace(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{
+		Groups: domain.GroupRBAC{
+			Enabled:      true,
+			Mode:         domain.GroupRBACModeMembers,
+			MemberExpiry: 24 * time.Hour,
+		},
+	})
+
+	err := usecase.applyRBAC(
+		context.Background(),
+		userNamespace,
+		domain.OnboardingRequest{UserName: testUserName, UserGroups: []string{"other-group"}},
+	)
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(t, "PruneRoleBindings")
+}
+
+func TestApplyRBAC_PruneFailure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupRBACUsecase(mockService, domain.RBAC{
+		Groups: domain.GroupRBAC{
+			Enabled:      true,
+			Mode:         domain.GroupRBACModeMembers,
+			MemberExpiry: 24 * time.Hour,
+		},
+	})
+
+	mockService.On("ApplyRoleBinding", mock.Anything, groupNamespace, mock.Anything).
+		Return(interfaces.RoleBindingUnchanged, nil)
+	mockService.On(
+		"PruneRoleBindings", mock.Anything, groupNamespace, mock.Anything, mock.Anything,
+	).Return(0, errors.New("forbidden"))
+
+	groupName := testGroupName
+	err := usecase.applyRBAC(
+		context.Background(),
+		groupNamespace,
+		domain.OnboardingRequest{Group: &groupName, UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to prune group member role bindings")
+}
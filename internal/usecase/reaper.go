@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+)
+
+type idleReaper struct {
+	namespaceService     interfaces.NamespaceService
+	idleNamespaceService interfaces.IdleNamespaceService
+	reaper               domain.Reaper
+	now                  func() time.Time
+}
+
+func NewIdleReaper(
+	namespaceService interfaces.NamespaceService,
+	idleNamespaceService interfaces.IdleNamespaceService,
+	reaper domain.Reaper,
+) domain.ReaperUsecase {
+	return &idleReaper{
+		namespaceService:     namespaceService,
+		idleNamespaceService: idleNamespaceService,
+		reaper:               reaper,
+		now:                  time.Now,
+	}
+}
+
+// Run reaps idle namespaces every interval until ctx is cancelled.
+func (r *idleReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.reaper.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Reap(ctx); err != nil {
+			slog.ErrorContext(ctx, "❌ Failed to reap idle namespaces", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *idleReaper) Reap(ctx context.Context) error {
+	namespaces, err := r.idleNamespaceService.ListIdleNamespaces(ctx, r.reaper.NamespaceLabels)
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	for _, namespace := range namespaces {
+		if isReserved(r.reaper.ReservedNames, namespace.Name) {
+			continue
+		}
+
+		action, ok := r.nextAction(namespace)
+		if !ok {
+			continue
+		}
+
+		attrs := []any{
+			slog.String("namespace", namespace.Name),
+			slog.String("action", string(action)),
+		}
+		if !namespace.LastLogin.IsZero() {
+			idleFor := r.now().Sub(namespace.LastLogin).Truncate(time.Minute)
+			attrs = append(attrs,
+				slog.Time("lastLogin", namespace.LastLogin),
+				slog.Duration("idleFor", idleFor),
+			)
+		}
+
+		if r.reaper.ReportOnly {
+			slog.InfoContext(ctx, "🔹 Report-only: idle namespace would be reaped", attrs...)
+			continue
+		}
+
+		if err := r.apply(ctx, namespace.Name, action); err != nil {
+			failures++
+			slog.ErrorContext(ctx, "❌ Failed to reap idle namespace",
+				append(attrs, slog.Any("error", err))...,
+			)
+			continue
+		}
+
+		slog.InfoContext(ctx, "✅ Reaped idle namespace", attrs...)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to reap %d idle namespaces", failures)
+	}
+	return nil
+}
+
+// nextAction returns the first action due that was not taken yet, so that actions escalate one
+// step per pass: a long idle namespace is warned, scaled down and archived before being deleted.
+func (r *idleReaper) nextAction(namespace domain.IdleNamespace) (domain.ReaperAction, bool) {
+	if namespace.Archived && !namespace.PurgeAfter.IsZero() &&
+		!r.now().Before(namespace.PurgeAfter) {
+		return domain.ReaperActionPurge, true
+	}
+	if namespace.LastLogin.IsZero() {
+		return "", false
+	}
+
+	idleFor := r.now().Sub(namespace.LastLogin)
+	steps := []struct {
+		action    domain.ReaperAction
+		threshold time.Duration
+		taken     bool
+	}{
+		{domain.ReaperActionWarn, r.reaper.WarnAfter, namespace.Warned || namespace.Archived},
+		{
+			domain.ReaperActionScaleDown,
+			r.reaper.ScaleDownAfter,
+			namespace.ScaledDown || namespace.Archived,
+		},
+		{domain.ReaperActionArchive, r.reaper.ArchiveAfter, namespace.Archived},
+		{domain.ReaperActionDelete, r.reaper.DeleteAfter, false},
+	}
+
+	for _, step := range steps {
+		if step.threshold <= 0 || step.taken {
+			continue
+		}
+		if idleFor < step.threshold {
+			return "", false
+		}
+		// 🔹 Only archived namespaces are deleted
+		if step.action == domain.ReaperActionDelete && !namespace.Archived {
+			return "", false
+		}
+		return step.action, true
+	}
+
+	return "", false
+}
+
+func (r *idleReaper) apply(ctx context.Context, namespace string, action domain.ReaperAction) error {
+	switch action {
+	case domain.ReaperActionWarn:
+		return r.idleNamespaceService.WarnNamespace(ctx, namespace)
+	case domain.ReaperActionScaleDown:
+		_, err := r.idleNamespaceService.ScaleDownNamespace(ctx, namespace)
+		return err
+	case domain.ReaperActionArchive:
+		_, err := r.namespaceService.OffboardNamespace(
+			ctx,
+			namespace,
+			"",
+			domain.OffboardingModeArchive,
+			r.reaper.GracePeriod,
+		)
+		return err
+	case domain.ReaperActionDelete, domain.ReaperActionPurge:
+		_, err := r.namespaceService.OffboardNamespace(
+			ctx,
+			namespace,
+			"",
+			domain.OffboardingModeDelete,
+			0,
+		)
+		return err
+	default:
+		return fmt.Errorf("unknown reaper action: %q", action)
+	}
+}
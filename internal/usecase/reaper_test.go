@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var reaperNow = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func setupReaper(
+	mockService *MockNamespaceService,
+	mockIdleService *MockIdleNamespaceService,
+	reportOnly bool,
+) *idleReaper {
+	return &idleReaper{
+		namespaceService:     mockService,
+		idleNamespaceService: mockIdleService,
+		reaper: domain.Reaper{
+			Enabled:        true,
+			ReportOnly:     reportOnly,
+			WarnAfter:      30 * 24 * time.Hour,
+			ScaleDownAfter: 60 * 24 * time.Hour,
+			ArchiveAfter:   90 * 24 * time.Hour,
+			DeleteAfter:    180 * 24 * time.Hour,
+			GracePeriod:    24 * time.Hour,
+		},
+		now: func() time.Time { return reaperNow },
+	}
+}
+
+func idleFor(name string, days int) domain.IdleNamespace {
+	return domain.IdleNamespace{
+		Name:      name,
+		LastLogin: reaperNow.Add(-time.Duration(days) * 24 * time.Hour),
+	}
+}
+
+// ✅ Test `Reap` escalates actions with the idle duration
+func Test_Reap_Escalation(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	mockIdleService := new(MockIdleNamespaceService)
+	reaper := setupReaper(mockService, mockIdleService, false)
+
+	alreadyWarned := idleFor("user-already-warned", 40)
+	alreadyWarned.Warned = true
+	scale := idleFor("user-scale", 70)
+	scale.Warned = true
+	archive := idleFor("user-archive", 100)
+	archive.Warned = true
+	archive.ScaledDown = true
+	archived := idleFor("user-delete", 200)
+	archived.Archived = true
+
+	mockIdleService.On("ListIdleNamespaces", mock.Anything, mock.Anything).
+		Return([]domain.IdleNamespace{
+			idleFor("user-active", 1),
+			idleFor("user-warn", 40),
+			alreadyWarned,
+			scale,
+			archive,
+			archived,
+		}, nil)
+	mockIdleService.On("WarnNamespace", mock.Anything, "user-warn").Return(nil)
+	mockIdleService.On("ScaleDownNamespace", mock.Anything, "user-scale").Return(2, nil)
+	mockService.On(
+		"OffboardNamespace",
+		mock.Anything,
+		"user-archive",
+		"",
+		domain.OffboardingModeArchive,
+		24*time.Hour,
+	).Return(interfaces.NamespaceArchived, nil)
+	mockService.On(
+		"OffboardNamespace",
+		mock.Anything,
+		"user-delete",
+		"",
+		domain.OffboardingModeDelete,
+		time.Duration(0),
+	).Return(interfaces.NamespaceDeleted, nil)
+
+	err := reaper.Reap(context.Background())
+
+	assert.NoError(t, err)
+	mockIdleService.AssertExpectations(t)
+	mockService.AssertExpectations(t)
+	mockIdleService.AssertNotCalled(t, "WarnNamespace", mock.Anything, "user-active")
+	mockIdleService.AssertNotCalled(t, "WarnNamespace", mock.Anything, "user-already-warned")
+}
+
+// ✅ Test `Reap` in report-only mode does not act on namespaces
+func Test_Reap_ReportOnly(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	mockIdleService := new(MockIdleNamespaceService)
+	reaper := setupReaper(mockService, mockIdleService, true)
+
+	mockIdleService.On("ListIdleNamespaces", mock.Anything, mock.Anything).
+		Return([]domain.IdleNamespace{
+			idleFor("user-warn", 40),
+			idleFor("user-delete", 200),
+		}, nil)
+
+	err := reaper.Reap(context.Background())
+
+	assert.NoError(t, err)
+	mockIdleService.AssertNotCalled(t, "WarnNamespace", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(
+		t,
+		"OffboardNamespace",
+		mock.Anything,
+		mock.Anything,
+		"",
+		mock.Anything,
+		mock.Anything,
+	)
+}
+
+// ✅ Test `Reap` does not archive an archived namespace again
+func Test_Reap_AlreadyArchived(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	mockIdleService := new(MockIdleNamespaceService)
+	reaper := setupReaper(mockService, mockIdleService, false)
+
+	archived := idleFor("user-archived", 100)
+	archived.Archived = true
+	mockIdleService.On("ListIdleNamespaces", mock.Anything, mock.Anything).
+		Return([]domain.IdleNamespace{archived}, nil)
+
+	err := reaper.Reap(context.Background())
+
+	assert.NoError(t, err)
+	mockService.AssertNotCalled(
+		t,
+		"OffboardNamespace",
+		mock.Anything,
+		mock.Anything,
+		"",
+		mock.Anything,
+		mock.Anything,
+	)
+}
+
+// ❌ Test `Reap` keeps going when an action fails
+func Test_Reap_ActionFailure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	mockIdleService := new(MockIdleNamespaceService)
+	reaper := setupReaper(mockService, mockIdleService, false)
+
+	scale := idleFor("user-scale", 70)
+	scale.Warned = true
+	mockIdleService.On("ListIdleNamespaces", mock.Anything, mock.Anything).
+		Return([]domain.IdleNamespace{scale, idleFor("user-warn", 40)}, nil)
+	mockIdleService.On("ScaleDownNamespace", mock.Anything, "user-scale").
+		Return(0, errors.New("failed to patch deployment"))
+	mockIdleService.On("WarnNamespace", mock.Anything, "user-warn").Return(nil)
+
+	err := reaper.Reap(context.Background())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reap 1 idle namespaces")
+	mockIdleService.AssertCalled(t, "WarnNamespace", mock.Anything, "user-warn")
+}
+
+// ✅ Test `Reap` escalates one step per pass and only deletes archived namespaces
+func Test_Reap_OneStepPerPass(t *testing.T) {
+	tests := map[string]struct {
+		update func(*domain.IdleNamespace)
+		action domain.ReaperAction
+	}{
+		"not warned": {
+			update: func(*domain.IdleNamespace) {},
+			action: domain.ReaperActionWarn,
+		},
+		"warned": {
+			update: func(namespace *domain.IdleNamespace) { namespace.Warned = true },
+			action: domain.ReaperActionScaleDown,
+		},
+		"scaled down": {
+			update: func(namespace *domain.IdleNamespace) {
+				namespace.Warned = true
+				namespace.ScaledDown = true
+			},
+			action: domain.ReaperActionArchive,
+		},
+		"archived": {
+			update: func(namespace *domain.IdleNamespace) { namespace.Archived = true },
+			action: domain.ReaperActionDelete,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			reaper := setupReaper(nil, nil, false)
+			namespace := idleFor("user-idle", 200)
+			tt.update(&namespace)
+
+			action, ok := reaper.nextAction(namespace)
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.action, action)
+		})
+	}
+}
+
+// ✅ Test `Reap` does not delete a namespace that was not archived, even without archiveAfter
+func Test_Reap_DeleteRequiresArchive(t *testing.T) {
+	reaper := setupReaper(nil, nil, false)
+	reaper.reaper.ArchiveAfter = 0
+	namespace := idleFor("user-idle", 200)
+	namespace.Warned = true
+	namespace.ScaledDown = true
+
+	_, ok := reaper.nextAction(namespace)
+
+	assert.False(t, ok)
+}
+
+// ✅ Test `Reap` purges archives past their grace period, even without a last login
+func Test_Reap_PurgesExpiredArchives(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	mockIdleService := new(MockIdleNamespaceService)
+	reaper := setupReaper(mockService, mockIdleService, false)
+
+	expired := idleFor("user-expired", 100)
+	expired.Archived = true
+	expired.PurgeAfter = reaperNow.Add(-time.Hour)
+	offboarded := domain.IdleNamespace{
+		Name:       "user-offboarded",
+		Archived:   true,
+		PurgeAfter: reaperNow.Add(-time.Hour),
+	}
+	pending := domain.IdleNamespace{
+		Name:       "user-pending",
+		Archived:   true,
+		PurgeAfter: reaperNow.Add(time.Hour),
+	}
+
+	mockIdleService.On("ListIdleNamespaces", mock.Anything, mock.Anything).
+		Return([]domain.IdleNamespace{expired, offboarded, pending}, nil)
+	for _, namespace := range []string{"user-expired", "user-offboarded"} {
+		mockService.On(
+			"OffboardNamespace",
+			mock.Anything,
+			namespace,
+			"",
+			domain.OffboardingModeDelete,
+			time.Duration(0),
+		).Return(interfaces.NamespaceDeleted, nil).Once()
+	}
+
+	err := reaper.Reap(context.Background())
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(
+		t,
+		"OffboardNamespace",
+		mock.Anything,
+		"user-pending",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	)
+}
+
+// ✅ Test `Reap` lists the configured namespaces and leaves reserved ones alone
+func Test_Reap_ReservedNamespaces(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	mockIdleService := new(MockIdleNamespaceService)
+	reaper := setupReaper(mockService, mockIdleService, false)
+	reaper.reaper.NamespaceLabels = map[string]string{"created-by": "onyxia"}
+	reaper.reaper.ReservedNames = []string{"kube-*"}
+
+	mockIdleService.On("ListIdleNamespaces", mock.Anything, reaper.reaper.NamespaceLabels).
+		Return([]domain.IdleNamespace{idleFor("kube-system", 200)}, nil)
+
+	err := reaper.Reap(context.Background())
+
+	assert.NoError(t, err)
+	mockIdleService.AssertExpectations(t)
+	mockService.AssertNotCalled(
+		t,
+		"OffboardNamespace",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+)
+
+// ReconcileQuotas applies the quota profile of their owner to every onboarded namespace, found
+// by the namespace labels, as an onboarding of this owner would, and prunes the expired member
+// RoleBindings of group namespaces. Archived namespaces are skipped, and so are namespaces
+// onboarded by versions that did not record their owner, until the owner onboards again.
+func (s *onboardingUsecase) ReconcileQuotas(
+	ctx context.Context,
+) (domain.QuotaReconcileSummary, error) {
+	var summary domain.QuotaReconcileSummary
+
+	if s.dryRun {
+		ctx = domain.WithDryRun(ctx)
+	}
+
+	// 🔹 Without labels, every namespace of the cluster would be reconciled
+	if len(s.namespace.NamespaceLabels) == 0 {
+		return summary, fmt.Errorf("namespace labels are required to find onboarded namespaces")
+	}
+
+	namespaces, err := s.namespaceService.ListNamespaces(ctx, s.namespace.NamespaceLabels)
+	if err != nil {
+		return summary, fmt.Errorf("failed to list onboarded namespaces: %w", err)
+	}
+
+	for _, namespace := range namespaces {
+		summary.Namespaces++
+
+		// 🔹 The zeroed quota of an archived namespace is kept until its owner is back
+		if namespace.Archived {
+			summary.Skipped++
+			continue
+		}
+
+		req, missing := s.reconcileRequest(namespace)
+		if missing != "" {
+			summary.Unknown++
+			slog.WarnContext(ctx, "⚠️ Owner of namespace is unknown, skipping its quota",
+				slog.String("namespace", namespace.Name),
+				slog.String("missingAnnotation", missing),
+			)
+			continue
+		}
+
+		// 🔹 Members who left a group never onboard into it again: their RoleBindings expire here
+		if req.Group != nil {
+			pruned, err := s.pruneGroupMemberRoleBindings(ctx, namespace.Name)
+			if err != nil {
+				summary.Failed++
+				continue
+			}
+			summary.Pruned += pruned
+		}
+
+		_, result, err := s.applyQuotas(ctx, namespace.Name, req)
+		if errors.Is(err, domain.ErrFieldManagerConflict) {
+			summary.Conflicts++
+			continue
+		}
+		if err != nil {
+			summary.Failed++
+			slog.ErrorContext(ctx, "❌ Failed to reconcile quota",
+				slog.String("namespace", namespace.Name),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		countQuotaResult(&summary, result)
+	}
+
+	if summary.Unknown > 0 {
+		slog.WarnContext(ctx, "⚠️ Namespaces of unknown owners wait for their owner to onboard",
+			slog.Int("namespaces", summary.Unknown),
+		)
+	}
+
+	if summary.Failed > 0 {
+		return summary, fmt.Errorf("failed to reconcile %d namespaces", summary.Failed)
+	}
+
+	return summary, nil
+}
+
+// reconcileRequest rebuilds the onboarding request of the owner of a namespace from its identity
+// and roles annotations, or returns the annotation missing to do so. The roles of a user are
+// unknown until their next onboarding, so a user namespace without the roles annotation is
+// skipped when role quotas are configured.
+func (s *onboardingUsecase) reconcileRequest(
+	namespace domain.ManagedNamespace,
+) (domain.OnboardingRequest, string) {
+	identity := namespace.Annotations[domain.IdentityAnnotation]
+
+	if group, ok := strings.CutPrefix(identity, "group:"); ok && group != "" {
+		return domain.OnboardingRequest{Group: &group}, ""
+	}
+
+	user, ok := strings.CutPrefix(identity, "user:")
+	if !ok || user == "" {
+		return domain.OnboardingRequest{}, domain.IdentityAnnotation
+	}
+
+	roles, ok := namespace.Annotations[domain.RolesAnnotation]
+	if !ok && len(s.quotas.Roles) > 0 {
+		return domain.OnboardingRequest{}, domain.RolesAnnotation
+	}
+
+	req := domain.OnboardingRequest{UserName: user}
+	if roles != "" {
+		req.UserRoles = strings.Split(roles, ",")
+	}
+	return req, ""
+}
+
+func countQuotaResult(
+	summary *domain.QuotaReconcileSummary,
+	result interfaces.QuotaApplicationResult,
+) {
+	switch result {
+	case interfaces.QuotaCreated:
+		summary.Created++
+	case interfaces.QuotaUpdated:
+		summary.Updated++
+	case interfaces.QuotaUnchanged:
+		summary.Unchanged++
+	case interfaces.QuotaIgnored, interfaces.QuotaPartiallyIgnored:
+		summary.Ignored++
+	case interfaces.QuotaDeleted:
+		summary.Deleted++
+	default:
+		summary.Skipped++ // quotas are disabled
+	}
+}
+
+type quotaReconciler struct {
+	onboarding domain.OnboardingUsecase
+	interval   time.Duration
+}
+
+func NewQuotaReconciler(
+	onboarding domain.OnboardingUsecase,
+	interval time.Duration,
+) domain.QuotaReconcilerUsecase {
+	return &quotaReconciler{onboarding: onboarding, interval: interval}
+}
+
+// Run reconciles the quotas of onboarded namespaces every interval until ctx is cancelled.
+func (r *quotaReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		summary, err := r.onboarding.ReconcileQuotas(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "❌ Failed to reconcile quotas", slog.Any("error", err))
+		}
+		slog.InfoContext(ctx, "✅ Quota reconciliation done",
+			slog.String("summary", summary.String()),
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var onboardedLabels = map[string]string{"created-by": "onyxia"}
+
+func setupReconcileUsecase(mockService *MockNamespaceService) *onboardingUsecase {
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{
+		Enabled: true,
+		Default: domain.Quota{CPURequest: "1"},
+		Group:   domain.Quota{CPURequest: "8"},
+		Roles:   map[string]domain.Quota{"admin": {CPURequest: "4"}},
+	})
+	usecase.quotas.GroupEnabled = true
+	usecase.namespace.NamespaceLabels = onboardedLabels
+	return usecase
+}
+
+func managedNamespace(name string, annotations ...string) domain.ManagedNamespace {
+	namespace := domain.ManagedNamespace{Name: name, Annotations: map[string]string{}}
+	for i := 0; i < len(annotations); i += 2 {
+		namespace.Annotations[annotations[i]] = annotations[i+1]
+	}
+	return namespace
+}
+
+// ✅ Test `ReconcileQuotas` applies the profile of the owner of each namespace
+func Test_ReconcileQuotas(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupReconcileUsecase(mockService)
+
+	mockService.On("ListNamespaces", mock.Anything, onboardedLabels).
+		Return([]domain.ManagedNamespace{
+			managedNamespace("user-admin",
+				domain.IdentityAnnotation, "user:admin",
+				domain.RolesAnnotation, "admin,viewer",
+			),
+			managedNamespace("user-jdoe",
+				domain.IdentityAnnotation, "user:jdoe",
+				domain.RolesAnnotation, "",
+			),
+			managedNamespace("projet-data", domain.IdentityAnnotation, "group:data"),
+			managedNamespace("projet-ops", domain.IdentityAnnotation, "group:ops"),
+			// 🔹 Onboarded before the roles annotation: its role quota cannot be known
+			managedNamespace("user-legacy", domain.IdentityAnnotation, "user:legacy"),
+			managedNamespace("user-unknown"),
+			{
+				Name:        "user-archived",
+				Annotations: map[string]string{domain.IdentityAnnotation: "user:archived"},
+				Archived:    true,
+			},
+		}, nil)
+	adminQuota := &domain.Quota{CPURequest: "4"}
+	mockService.On("ApplyResourceQuotas", mock.Anything, "user-admin", adminQuota).
+		Return(interfaces.QuotaUpdated, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, "user-admin").
+		Return(interfaces.LimitRangeUnchanged, nil)
+	mockService.On("ApplyResourceQuotas", mock.Anything, "user-jdoe", &usecase.quotas.Default).
+		Return(interfaces.QuotaUnchanged, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, "user-jdoe").
+		Return(interfaces.LimitRangeUnchanged, nil)
+	mockService.On("ApplyResourceQuotas", mock.Anything, "projet-data", &usecase.quotas.Group).
+		Return(interfaces.QuotaIgnored, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, "projet-data").
+		Return(interfaces.LimitRangeUnchanged, nil)
+	mockService.On("ApplyResourceQuotas", mock.Anything, "projet-ops", &usecase.quotas.Group).
+		Return(interfaces.QuotaApplicationResult(""), fmt.Errorf(
+			"%w: resource quota projet-ops/onyxia-quota", domain.ErrFieldManagerConflict,
+		))
+
+	summary, err := usecase.ReconcileQuotas(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.QuotaReconcileSummary{
+		Namespaces: 7,
+		Updated:    1,
+		Unchanged:  1,
+		Ignored:    1,
+		Conflicts:  1,
+		Skipped:    1,
+		Unknown:    2,
+	}, summary)
+	mockService.AssertExpectations(t)
+}
+
+// ✅ Test a failure on one namespace does not stop the reconciliation
+func Test_ReconcileQuotas_Failure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupReconcileUsecase(mockService)
+
+	mockService.On("ListNamespaces", mock.Anything, onboardedLabels).
+		Return([]domain.ManagedNamespace{
+			managedNamespace("projet-a", domain.IdentityAnnotation, "group:a"),
+			managedNamespace("projet-b", domain.IdentityAnnotation, "group:b"),
+		}, nil)
+	mockService.On("ApplyResourceQuotas", mock.Anything, "projet-a", mock.Anything).
+		Return(interfaces.QuotaApplicationResult(""), errors.New("forbidden"))
+	mockService.On("ApplyResourceQuotas", mock.Anything, "projet-b", mock.Anything).
+		Return(interfaces.QuotaCreated, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, "projet-b").
+		Return(interfaces.LimitRangeUnchanged, nil)
+
+	summary, err := usecase.ReconcileQuotas(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, 1, summary.Created)
+}
+
+// ✅ Test `ReconcileQuotas` prunes expired member RoleBindings of group namespaces
+func Test_ReconcileQuotas_PrunesGroupMembers(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupReconcileUsecase(mockService)
+	usecase.rbac.Groups = domain.GroupRBAC{
+		Enabled:      true,
+		Mode:         domain.GroupRBACModeMembers,
+		MemberExpiry: 24 * time.Hour,
+	}
+
+	mockService.On("ListNamespaces", mock.Anything, onboardedLabels).
+		Return([]domain.ManagedNamespace{
+			managedNamespace("projet-data", domain.IdentityAnnotation, "group:data"),
+			managedNamespace("user-jdoe",
+				domain.IdentityAnnotation, "user:jdoe",
+				domain.RolesAnnotation, "",
+			),
+		}, nil)
+	mockService.On(
+		"PruneRoleBindings", mock.Anything, "projet-data", groupMemberLabel, mock.Anything,
+	).Return(2, nil)
+	mockService.On("ApplyResourceQuotas", mock.Anything, mock.Anything, mock.Anything).
+		Return(interfaces.QuotaUnchanged, nil)
+	mockService.On("DeleteLimitRange", mock.Anything, mock.Anything).
+		Return(interfaces.LimitRangeUnchanged, nil)
+
+	summary, err := usecase.ReconcileQuotas(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Pruned)
+	assert.Equal(t, 2, summary.Unchanged)
+	mockService.AssertNumberOfCalls(t, "PruneRoleBindings", 1)
+}
+
+// ✅ Test `ReconcileQuotas` refuses to run without namespace labels
+func Test_ReconcileQuotas_NoLabels(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupReconcileUsecase(mockService)
+	usecase.namespace.NamespaceLabels = nil
+
+	_, err := usecase.ReconcileQuotas(context.Background())
+
+	assert.Error(t, err)
+	mockService.AssertNotCalled(t, "ListNamespaces", mock.Anything, mock.Anything)
+}
+
+// ✅ Test `reconcileRequest` reports the annotation missing to rebuild the request of the owner
+func Test_ReconcileRequest_MissingAnnotation(t *testing.T) {
+	usecase := setupReconcileUsecase(nil)
+
+	tests := map[string]struct {
+		namespace domain.ManagedNamespace
+		missing   string
+	}{
+		"group": {
+			namespace: managedNamespace("projet-data", domain.IdentityAnnotation, "group:data"),
+		},
+		"user": {
+			namespace: managedNamespace("user-jdoe",
+				domain.IdentityAnnotation, "user:jdoe",
+				domain.RolesAnnotation, "",
+			),
+		},
+		"no identity": {
+			namespace: managedNamespace("user-unknown"),
+			missing:   domain.IdentityAnnotation,
+		},
+		"no roles": {
+			namespace: managedNamespace("user-legacy", domain.IdentityAnnotation, "user:legacy"),
+			missing:   domain.RolesAnnotation,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, missing := usecase.reconcileRequest(tt.namespace)
+
+			assert.Equal(t, tt.missing, missing)
+		})
+	}
+}
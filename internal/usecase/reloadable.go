@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+)
+
+// ReloadableOnboardingUsecase delegates to an onboarding usecase that can be swapped when the
+// configuration changes. A request runs with the usecase current when it started, so it never
+// sees two configurations.
+type ReloadableOnboardingUsecase struct {
+	mu      sync.RWMutex
+	usecase domain.OnboardingUsecase
+}
+
+var _ domain.OnboardingUsecase = (*ReloadableOnboardingUsecase)(nil)
+
+func NewReloadableOnboardingUsecase(
+	usecase domain.OnboardingUsecase,
+) *ReloadableOnboardingUsecase {
+	return &ReloadableOnboardingUsecase{usecase: usecase}
+}
+
+// Reload replaces the usecase serving the next requests.
+func (r *ReloadableOnboardingUsecase) Reload(usecase domain.OnboardingUsecase) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.usecase = usecase
+}
+
+func (r *ReloadableOnboardingUsecase) current() domain.OnboardingUsecase {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.usecase
+}
+
+func (r *ReloadableOnboardingUsecase) Onboard(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (domain.OnboardingResult, error) {
+	return r.current().Onboard(ctx, req)
+}
+
+func (r *ReloadableOnboardingUsecase) Offboard(
+	ctx context.Context,
+	req domain.OffboardingRequest,
+) error {
+	return r.current().Offboard(ctx, req)
+}
+
+func (r *ReloadableOnboardingUsecase) Status(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (domain.NamespaceStatus, error) {
+	return r.current().Status(ctx, req)
+}
+
+func (r *ReloadableOnboardingUsecase) ReconcileQuotas(
+	ctx context.Context,
+) (domain.QuotaReconcileSummary, error) {
+	return r.current().ReconcileQuotas(ctx)
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ✅ Test requests use the usecase of the last reload
+func TestReloadableOnboardingUsecase_Reload(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	mockService.On("GetNamespaceStatus", mock.Anything, mock.Anything, mock.Anything).
+		Return(domain.NamespaceStatus{}, nil)
+
+	reloadable := NewReloadableOnboardingUsecase(setupPrivateUsecase(mockService, domain.Quotas{}))
+	req := domain.OnboardingRequest{UserName: testUserName}
+
+	_, err := reloadable.Status(context.Background(), req)
+	assert.NoError(t, err)
+
+	reloaded := setupPrivateUsecase(mockService, domain.Quotas{})
+	reloaded.namespace.NamespacePrefix = "u-"
+	reloadable.Reload(reloaded)
+
+	_, err = reloadable.Status(context.Background(), req)
+	assert.NoError(t, err)
+
+	mockService.AssertCalled(t, "GetNamespaceStatus", mock.Anything, userNamespace, mock.Anything)
+	mockService.AssertCalled(
+		t, "GetNamespaceStatus", mock.Anything, "u-"+testUserName, mock.Anything,
+	)
+}
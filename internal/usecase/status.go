@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+)
+
+func (s *onboardingUsecase) Status(
+	ctx context.Context,
+	req domain.OnboardingRequest,
+) (domain.NamespaceStatus, error) {
+	namespace, err := s.getNamespace(ctx, req)
+	if err != nil {
+		return domain.NamespaceStatus{}, err
+	}
+
+	status, err := s.namespaceService.GetNamespaceStatus(ctx, namespace, getIdentity(req))
+	if err != nil {
+		slog.ErrorContext(ctx, "❌ Failed to get namespace status",
+			slog.String("namespace", namespace),
+			slog.Any("error", err),
+		)
+		return domain.NamespaceStatus{}, err
+	}
+
+	status.Annotations = filterKeys(status.Annotations, s.managedAnnotationKeys())
+	status.Labels = filterKeys(status.Labels, s.managedLabelKeys())
+
+	return status, nil
+}
+
+// managedAnnotationKeys returns the annotations set on namespaces by the onboarding.
+func (s *onboardingUsecase) managedAnnotationKeys() map[string]string {
+	keys := map[string]string{domain.IdentityAnnotation: "", domain.RolesAnnotation: ""}
+	if s.namespace.SkipIfOnboardedWithin > 0 {
+		keys[domain.FingerprintAnnotation] = ""
+	}
+	if !s.namespace.Annotation.Enabled {
+		return keys
+	}
+
+	for key := range s.namespace.Annotation.Static {
+		keys[key] = ""
+	}
+	if s.namespace.Annotation.Dynamic.LastLoginTimestamp {
+		keys[domain.LastLoginAnnotation] = ""
+	}
+	for _, attr := range s.namespace.Annotation.Dynamic.UserAttributes {
+		keys[attr] = ""
+	}
+
+	return keys
+}
+
+// managedLabelKeys returns the labels set on namespaces by the onboarding.
+func (s *onboardingUsecase) managedLabelKeys() map[string]string {
+	keys := maps.Clone(s.namespace.NamespaceLabels)
+	if keys == nil {
+		keys = make(map[string]string)
+	}
+	for _, label := range s.namespace.DynamicLabels {
+		keys[label.Name] = ""
+	}
+	return keys
+}
+
+// filterKeys returns the entries of values whose key is in keys.
+func filterKeys(values, keys map[string]string) map[string]string {
+	result := make(map[string]string)
+	for key, value := range values {
+		if _, ok := keys[key]; ok {
+			result[key] = value
+		}
+	}
+	return result
+}
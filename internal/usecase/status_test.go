@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onyxia-datalab/onyxia-onboarding/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ✅ Test `Status` only returns the annotations and labels managed by the onboarding
+func Test_Status_ManagedMetadata(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+	usecase.namespace.NamespaceLabels = map[string]string{"created-by": "onyxia"}
+	usecase.namespace.Annotation = domain.Annotation{
+		Enabled: true,
+		Static:  map[string]string{"onyxia.sh/team": "data"},
+	}
+
+	quota := &domain.QuotaStatus{Hard: map[string]string{"requests.cpu": "4"}}
+	mockService.On("GetNamespaceStatus", mock.Anything, groupNamespace, "group:"+testGroupName).
+		Return(domain.NamespaceStatus{
+			Name:   groupNamespace,
+			Exists: true,
+			Annotations: map[string]string{
+				"onyxia.sh/team":          "data",
+				domain.IdentityAnnotation: "group:" + testGroupName,
+				"kubectl.kubernetes.io/x": "unmanaged",
+			},
+			Labels: map[string]string{
+				"created-by":                  "onyxia",
+				"kubernetes.io/metadata.name": groupNamespace,
+			},
+			Quota: quota,
+		}, nil)
+
+	group := testGroupName
+	status, err := usecase.Status(
+		context.Background(),
+		domain.OnboardingRequest{Group: &group, UserName: testUserName},
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, status.Exists)
+	assert.Equal(t, map[string]string{
+		"onyxia.sh/team":          "data",
+		domain.IdentityAnnotation: "group:" + testGroupName,
+	}, status.Annotations)
+	assert.Equal(t, map[string]string{"created-by": "onyxia"}, status.Labels)
+	assert.Equal(t, quota, status.Quota)
+}
+
+// ❌ Test `Status` Failure
+func Test_Status_Failure(t *testing.T) {
+	mockService := new(MockNamespaceService)
+	usecase := setupPrivateUsecase(mockService, domain.Quotas{})
+
+	mockService.On("GetNamespaceStatus", mock.Anything, userNamespace, "user:"+testUserName).
+		Return(domain.NamespaceStatus{}, errors.New("API server unavailable"))
+
+	_, err := usecase.Status(
+		context.Background(),
+		domain.OnboardingRequest{UserName: testUserName},
+	)
+
+	assert.Error(t, err)
+}